@@ -0,0 +1,568 @@
+// Package cdcl is a small conflict-driven clause learning SAT solver with
+// two watched literals, VSIDS branching, phase saving, Luby restarts and
+// LBD-based learnt clause deletion.
+//
+// It is tuned for the short, highly structured instances produced by sudoku
+// encodings, where most variables end up false: decisions default to the
+// negative phase and restarts are frequent.
+package cdcl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+const (
+	restartUnit     = 64
+	varDecay        = 0.95
+	clauseDecay     = 0.999
+	minLearnts      = 2000
+	learntsFraction = 3
+	learntsGrowth   = 1.1
+)
+
+type Stats struct {
+	Conflicts    int
+	Decisions    int
+	Propagations int
+	Restarts     int
+}
+
+type Solver struct {
+	Stats
+
+	clauses []*clause
+	learnts []*clause
+	watches [][]watcher // lit -> clauses watching it
+
+	assigns  []int8 // var -> lTrue, lFalse or lUndef
+	level    []int
+	reason   []*clause
+	polarity []bool // var -> last assigned value was false
+	seen     []bool
+	trail    []lit
+	trailLim []int
+	qhead    int
+
+	activity []float64
+	varInc   float64
+	order    varHeap
+	claInc   float64
+
+	levelStamp []int // decision level -> last lbd computation that saw it
+	lbdStamp   int
+
+	maxLearnts float64
+	unsat      bool
+	model      []bool
+	addBuf     []lit
+}
+
+func New() *Solver {
+	s := &Solver{
+		varInc: 1,
+		claInc: 1,
+	}
+	s.order.activity = &s.activity
+	s.ensureVar(0)
+	return s
+}
+
+// NumVars is the largest variable seen so far.
+func (s *Solver) NumVars() int {
+	return len(s.assigns) - 1
+}
+
+// Add adds a DIMACS literal to the clause being built, 0 ends the clause.
+func (s *Solver) Add(x int) {
+	if x == 0 {
+		s.addClause(s.addBuf)
+		s.addBuf = s.addBuf[:0]
+		return
+	}
+	s.ensureVar(abs(x))
+	s.addBuf = append(s.addBuf, fromDimacs(x))
+}
+
+// AddClause adds a clause of DIMACS literals.
+func (s *Solver) AddClause(lits ...int) {
+	for _, x := range lits {
+		if x != 0 {
+			s.Add(x)
+		}
+	}
+	s.Add(0)
+}
+
+// Solve returns 1 if the formula is satisfiable, -1 if it is not. Clauses can
+// be added between calls.
+func (s *Solver) Solve() int {
+	s.model = nil
+	if s.unsat {
+		return -1
+	}
+	s.cancelUntil(0)
+	if s.propagate() != nil {
+		s.unsat = true
+		return -1
+	}
+	s.maxLearnts = math.Max(float64(len(s.clauses))/learntsFraction, minLearnts)
+
+	for {
+		status := s.search(int(luby(s.Restarts) * restartUnit))
+		if status != 0 {
+			return status
+		}
+		s.Restarts++
+	}
+}
+
+// Value returns the value of DIMACS literal x in the last model found.
+func (s *Solver) Value(x int) bool {
+	v := abs(x)
+	if v >= len(s.model) {
+		return x < 0
+	}
+	return s.model[v] == (x > 0)
+}
+
+// Write writes the problem clauses in DIMACS format. Literals fixed at the
+// top level are written as unit clauses and learnt clauses are omitted.
+func (s *Solver) Write(w io.Writer) error {
+	units := s.trail
+	if len(s.trailLim) > 0 {
+		units = s.trail[:s.trailLim[0]]
+	}
+	numClauses := len(units) + len(s.clauses)
+	if s.unsat {
+		numClauses++
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "p cnf %d %d\n", s.NumVars(), numClauses)
+	if s.unsat {
+		fmt.Fprintln(bw, "0")
+	}
+	for _, l := range units {
+		fmt.Fprintf(bw, "%d 0\n", l.dimacs())
+	}
+	for _, c := range s.clauses {
+		for _, l := range c.lits {
+			fmt.Fprintf(bw, "%d ", l.dimacs())
+		}
+		fmt.Fprintln(bw, "0")
+	}
+	return bw.Flush()
+}
+
+func (s *Solver) ensureVar(v int) {
+	for len(s.assigns) <= v {
+		n := len(s.assigns)
+		s.assigns = append(s.assigns, lUndef)
+		s.level = append(s.level, 0)
+		s.reason = append(s.reason, nil)
+		s.polarity = append(s.polarity, true)
+		s.seen = append(s.seen, false)
+		s.activity = append(s.activity, 0)
+		s.watches = append(s.watches, nil, nil)
+		if n > 0 {
+			s.order.insert(n)
+		}
+	}
+}
+
+func (s *Solver) addClause(ps []lit) {
+	if s.unsat {
+		return
+	}
+	s.cancelUntil(0)
+
+	lits := make([]lit, 0, len(ps))
+	sort.Slice(ps, func(i, j int) bool { return ps[i] < ps[j] })
+	for i, l := range ps {
+		switch {
+		case s.value(l) == lTrue, i > 0 && l == ps[i-1].neg():
+			// satisfied or tautology
+			return
+		case s.value(l) == lFalse, i > 0 && l == ps[i-1]:
+			continue
+		}
+		lits = append(lits, l)
+	}
+
+	switch len(lits) {
+	case 0:
+		s.unsat = true
+	case 1:
+		s.enqueue(lits[0], nil)
+		if s.propagate() != nil {
+			s.unsat = true
+		}
+	default:
+		c := &clause{lits: lits}
+		s.clauses = append(s.clauses, c)
+		s.attach(c)
+	}
+}
+
+func (s *Solver) search(maxConflicts int) int {
+	conflicts := 0
+	for {
+		confl := s.propagate()
+		if confl != nil {
+			s.Conflicts++
+			conflicts++
+			if s.decisionLevel() == 0 {
+				s.unsat = true
+				return -1
+			}
+
+			learnt, btLevel, lbd := s.analyze(confl)
+			s.cancelUntil(btLevel)
+			if len(learnt) == 1 {
+				s.enqueue(learnt[0], nil)
+			} else {
+				c := &clause{lits: learnt, learnt: true, lbd: lbd}
+				s.learnts = append(s.learnts, c)
+				s.attach(c)
+				s.bumpClause(c)
+				s.enqueue(learnt[0], c)
+			}
+			s.varInc /= varDecay
+			s.claInc /= clauseDecay
+			continue
+		}
+
+		if conflicts >= maxConflicts {
+			s.cancelUntil(0)
+			return 0
+		}
+		if float64(len(s.learnts)-len(s.trail)) >= s.maxLearnts {
+			s.reduceLearnts()
+			s.maxLearnts *= learntsGrowth
+		}
+
+		next := s.pickBranchLit()
+		if next == litUndef {
+			s.saveModel()
+			return 1
+		}
+		s.Decisions++
+		s.trailLim = append(s.trailLim, len(s.trail))
+		s.enqueue(next, nil)
+	}
+}
+
+func (s *Solver) propagate() *clause {
+	for s.qhead < len(s.trail) {
+		p := s.trail[s.qhead]
+		s.qhead++
+		s.Propagations++
+
+		falseLit := p.neg()
+		ws := s.watches[falseLit]
+		i, j := 0, 0
+		for i < len(ws) {
+			w := ws[i]
+			i++
+			if s.value(w.blocker) == lTrue {
+				ws[j] = w
+				j++
+				continue
+			}
+
+			// make sure the false literal is lits[1]
+			c := w.c
+			if c.lits[0] == falseLit {
+				c.lits[0], c.lits[1] = c.lits[1], falseLit
+			}
+			first := c.lits[0]
+			if first != w.blocker && s.value(first) == lTrue {
+				ws[j] = watcher{c, first}
+				j++
+				continue
+			}
+
+			// look for a new literal to watch
+			found := false
+			for k := 2; k < len(c.lits); k++ {
+				if s.value(c.lits[k]) != lFalse {
+					c.lits[1], c.lits[k] = c.lits[k], falseLit
+					s.watches[c.lits[1]] = append(s.watches[c.lits[1]], watcher{c, first})
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+
+			// unit or conflicting
+			ws[j] = watcher{c, first}
+			j++
+			if s.value(first) == lFalse {
+				j += copy(ws[j:], ws[i:])
+				s.watches[falseLit] = ws[:j]
+				s.qhead = len(s.trail)
+				return c
+			}
+			s.enqueue(first, c)
+		}
+		s.watches[falseLit] = ws[:j]
+	}
+	return nil
+}
+
+// analyze derives the first-UIP clause of the conflict, returning it with the
+// asserting literal first, the level to backjump to and its LBD.
+func (s *Solver) analyze(confl *clause) ([]lit, int, int) {
+	learnt := []lit{litUndef}
+	pathC := 0
+	p := litUndef
+	idx := len(s.trail) - 1
+	c := confl
+
+	for {
+		if c.learnt {
+			s.bumpClause(c)
+		}
+		start := 0
+		if p != litUndef {
+			start = 1
+		}
+		for _, q := range c.lits[start:] {
+			v := q.v()
+			if s.seen[v] || s.level[v] == 0 {
+				continue
+			}
+			s.bumpVar(v)
+			s.seen[v] = true
+			if s.level[v] >= s.decisionLevel() {
+				pathC++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+
+		for !s.seen[s.trail[idx].v()] {
+			idx--
+		}
+		p = s.trail[idx]
+		idx--
+		c = s.reason[p.v()]
+		s.seen[p.v()] = false
+		pathC--
+		if pathC == 0 {
+			break
+		}
+	}
+	learnt[0] = p.neg()
+
+	// drop literals implied by the rest of the clause
+	all := append([]lit(nil), learnt...)
+	j := 1
+	for _, q := range learnt[1:] {
+		if !s.redundant(q) {
+			learnt[j] = q
+			j++
+		}
+	}
+	learnt = learnt[:j]
+	for _, q := range all {
+		s.seen[q.v()] = false
+	}
+
+	btLevel := 0
+	if len(learnt) > 1 {
+		maxI := 1
+		for i := 2; i < len(learnt); i++ {
+			if s.level[learnt[i].v()] > s.level[learnt[maxI].v()] {
+				maxI = i
+			}
+		}
+		learnt[1], learnt[maxI] = learnt[maxI], learnt[1]
+		btLevel = s.level[learnt[1].v()]
+	}
+
+	return learnt, btLevel, s.lbd(learnt)
+}
+
+func (s *Solver) redundant(q lit) bool {
+	r := s.reason[q.v()]
+	if r == nil {
+		return false
+	}
+	for _, l := range r.lits[1:] {
+		if !s.seen[l.v()] && s.level[l.v()] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Solver) lbd(lits []lit) int {
+	s.lbdStamp++
+	for len(s.levelStamp) <= s.decisionLevel() {
+		s.levelStamp = append(s.levelStamp, 0)
+	}
+	n := 0
+	for _, l := range lits {
+		level := s.level[l.v()]
+		if s.levelStamp[level] != s.lbdStamp {
+			s.levelStamp[level] = s.lbdStamp
+			n++
+		}
+	}
+	return n
+}
+
+// reduceLearnts removes half of the learnt clauses, keeping glue clauses and
+// the ones currently used as a reason.
+func (s *Solver) reduceLearnts() {
+	sort.Slice(s.learnts, func(i, j int) bool {
+		a, b := s.learnts[i], s.learnts[j]
+		if a.lbd != b.lbd {
+			return a.lbd > b.lbd
+		}
+		return a.activity < b.activity
+	})
+
+	half := len(s.learnts) / 2
+	kept := s.learnts[:0]
+	for i, c := range s.learnts {
+		if i < half && c.lbd > 2 && !s.locked(c) {
+			c.deleted = true
+			continue
+		}
+		kept = append(kept, c)
+	}
+	s.learnts = kept
+
+	for l, ws := range s.watches {
+		j := 0
+		for _, w := range ws {
+			if !w.c.deleted {
+				ws[j] = w
+				j++
+			}
+		}
+		s.watches[l] = ws[:j]
+	}
+}
+
+func (s *Solver) locked(c *clause) bool {
+	v := c.lits[0].v()
+	return s.reason[v] == c && s.value(c.lits[0]) == lTrue
+}
+
+func (s *Solver) pickBranchLit() lit {
+	for !s.order.empty() {
+		v := s.order.removeMax()
+		if s.assigns[v] == lUndef {
+			return mkLit(v, s.polarity[v])
+		}
+	}
+	return litUndef
+}
+
+func (s *Solver) enqueue(l lit, from *clause) {
+	v := l.v()
+	if l.sign() {
+		s.assigns[v] = lFalse
+	} else {
+		s.assigns[v] = lTrue
+	}
+	s.level[v] = s.decisionLevel()
+	s.reason[v] = from
+	s.trail = append(s.trail, l)
+}
+
+func (s *Solver) cancelUntil(level int) {
+	if s.decisionLevel() <= level {
+		return
+	}
+	for i := len(s.trail) - 1; i >= s.trailLim[level]; i-- {
+		v := s.trail[i].v()
+		s.polarity[v] = s.trail[i].sign()
+		s.assigns[v] = lUndef
+		s.reason[v] = nil
+		if !s.order.contains(v) {
+			s.order.insert(v)
+		}
+	}
+	s.trail = s.trail[:s.trailLim[level]]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = len(s.trail)
+}
+
+func (s *Solver) decisionLevel() int {
+	return len(s.trailLim)
+}
+
+func (s *Solver) value(l lit) int8 {
+	a := s.assigns[l.v()]
+	if l.sign() {
+		return -a
+	}
+	return a
+}
+
+func (s *Solver) attach(c *clause) {
+	s.watches[c.lits[0]] = append(s.watches[c.lits[0]], watcher{c, c.lits[1]})
+	s.watches[c.lits[1]] = append(s.watches[c.lits[1]], watcher{c, c.lits[0]})
+}
+
+func (s *Solver) bumpVar(v int) {
+	s.activity[v] += s.varInc
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.varInc *= 1e-100
+	}
+	if s.order.contains(v) {
+		s.order.increased(v)
+	}
+}
+
+func (s *Solver) bumpClause(c *clause) {
+	c.activity += s.claInc
+	if c.activity > 1e20 {
+		for _, l := range s.learnts {
+			l.activity *= 1e-20
+		}
+		s.claInc *= 1e-20
+	}
+}
+
+func (s *Solver) saveModel() {
+	s.model = make([]bool, len(s.assigns))
+	for v := 1; v < len(s.assigns); v++ {
+		s.model[v] = s.assigns[v] == lTrue
+	}
+}
+
+// luby returns the i-th element (0-indexed) of the Luby sequence 1 1 2 1 1 2 4 ...
+func luby(i int) float64 {
+	size, seq := 1, 0
+	for size < i+1 {
+		seq++
+		size = 2*size + 1
+	}
+	for size-1 != i {
+		size = (size - 1) >> 1
+		seq--
+		i = i % size
+	}
+	return math.Pow(2, float64(seq))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,124 @@
+package cdcl
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimple(t *testing.T) {
+	s := New()
+	s.AddClause(1, 2)
+	s.AddClause(-1, 2)
+	s.AddClause(1, -2)
+	assert.Equal(t, 1, s.Solve())
+	assert.True(t, s.Value(1))
+	assert.True(t, s.Value(2))
+
+	s.AddClause(-1, -2)
+	assert.Equal(t, -1, s.Solve())
+}
+
+func TestEmptyClause(t *testing.T) {
+	s := New()
+	s.AddClause(1)
+	s.Add(0)
+	assert.Equal(t, -1, s.Solve())
+}
+
+func TestPigeonhole(t *testing.T) {
+	// 6 pigeons, 5 holes
+	pigeons, holes := 6, 5
+	x := func(p, h int) int { return p*holes + h + 1 }
+	s := New()
+	for p := 0; p < pigeons; p++ {
+		clause := []int{}
+		for h := 0; h < holes; h++ {
+			clause = append(clause, x(p, h))
+		}
+		s.AddClause(clause...)
+	}
+	for h := 0; h < holes; h++ {
+		for p := 0; p < pigeons; p++ {
+			for q := p + 1; q < pigeons; q++ {
+				s.AddClause(-x(p, h), -x(q, h))
+			}
+		}
+	}
+	assert.Equal(t, -1, s.Solve())
+}
+
+func TestRandom3SAT(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const numVars = 12
+	for round := 0; round < 300; round++ {
+		clauses := make([][]int, 40+rng.Intn(30))
+		for i := range clauses {
+			for j := 0; j < 3; j++ {
+				x := 1 + rng.Intn(numVars)
+				if rng.Intn(2) == 0 {
+					x = -x
+				}
+				clauses[i] = append(clauses[i], x)
+			}
+		}
+
+		s := New()
+		for _, c := range clauses {
+			s.AddClause(c...)
+		}
+		status := s.Solve()
+		assert.Equal(t, bruteForce(numVars, clauses), status == 1)
+		if status == 1 {
+			for _, c := range clauses {
+				assert.True(t, s.Value(c[0]) || s.Value(c[1]) || s.Value(c[2]))
+			}
+		}
+	}
+}
+
+func TestIncrementalCountsModels(t *testing.T) {
+	// exactly one of 1..4, so 4 models
+	s := New()
+	s.AddClause(1, 2, 3, 4)
+	for i := 1; i <= 4; i++ {
+		for j := i + 1; j <= 4; j++ {
+			s.AddClause(-i, -j)
+		}
+	}
+	count := 0
+	for s.Solve() == 1 {
+		count++
+		block := []int{}
+		for v := 1; v <= 4; v++ {
+			if s.Value(v) {
+				block = append(block, -v)
+			}
+		}
+		s.AddClause(block...)
+	}
+	assert.Equal(t, 4, count)
+}
+
+func bruteForce(numVars int, clauses [][]int) bool {
+	for m := 0; m < 1<<numVars; m++ {
+		value := func(x int) bool {
+			if x < 0 {
+				return m&(1<<(-x-1)) == 0
+			}
+			return m&(1<<(x-1)) != 0
+		}
+		sat := true
+		for _, c := range clauses {
+			if !value(c[0]) && !value(c[1]) && !value(c[2]) {
+				sat = false
+				break
+			}
+		}
+		if sat {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package cdcl
+
+const (
+	lTrue  int8 = 1
+	lFalse int8 = -1
+	lUndef int8 = 0
+)
+
+// lit is 2*var for the positive literal and 2*var+1 for the negative one.
+type lit uint32
+
+const litUndef lit = 0
+
+func mkLit(v int, negative bool) lit {
+	if negative {
+		return lit(2*v + 1)
+	}
+	return lit(2 * v)
+}
+
+func fromDimacs(x int) lit {
+	if x < 0 {
+		return mkLit(-x, true)
+	}
+	return mkLit(x, false)
+}
+
+func (l lit) v() int {
+	return int(l >> 1)
+}
+
+func (l lit) sign() bool {
+	return l&1 == 1
+}
+
+func (l lit) neg() lit {
+	return l ^ 1
+}
+
+func (l lit) dimacs() int {
+	if l.sign() {
+		return -l.v()
+	}
+	return l.v()
+}
+
+type clause struct {
+	lits     []lit
+	learnt   bool
+	deleted  bool
+	lbd      int
+	activity float64
+}
+
+type watcher struct {
+	c       *clause
+	blocker lit
+}
+
+// varHeap is a max-heap of variables ordered by activity.
+type varHeap struct {
+	activity *[]float64
+	heap     []int
+	indices  []int // var -> position in heap, -1 if absent
+}
+
+func (h *varHeap) empty() bool {
+	return len(h.heap) == 0
+}
+
+func (h *varHeap) contains(v int) bool {
+	return v < len(h.indices) && h.indices[v] >= 0
+}
+
+func (h *varHeap) less(a, b int) bool {
+	return (*h.activity)[a] > (*h.activity)[b]
+}
+
+func (h *varHeap) insert(v int) {
+	for len(h.indices) <= v {
+		h.indices = append(h.indices, -1)
+	}
+	h.indices[v] = len(h.heap)
+	h.heap = append(h.heap, v)
+	h.up(h.indices[v])
+}
+
+func (h *varHeap) increased(v int) {
+	h.up(h.indices[v])
+}
+
+func (h *varHeap) removeMax() int {
+	v := h.heap[0]
+	last := h.heap[len(h.heap)-1]
+	h.heap = h.heap[:len(h.heap)-1]
+	h.indices[v] = -1
+	if len(h.heap) > 0 {
+		h.heap[0] = last
+		h.indices[last] = 0
+		h.down(0)
+	}
+	return v
+}
+
+func (h *varHeap) up(i int) {
+	v := h.heap[i]
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(v, h.heap[parent]) {
+			break
+		}
+		h.heap[i] = h.heap[parent]
+		h.indices[h.heap[i]] = i
+		i = parent
+	}
+	h.heap[i] = v
+	h.indices[v] = i
+}
+
+func (h *varHeap) down(i int) {
+	v := h.heap[i]
+	n := len(h.heap)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if child+1 < n && h.less(h.heap[child+1], h.heap[child]) {
+			child++
+		}
+		if !h.less(h.heap[child], v) {
+			break
+		}
+		h.heap[i] = h.heap[child]
+		h.indices[h.heap[i]] = i
+		i = child
+	}
+	h.heap[i] = v
+	h.indices[v] = i
+}
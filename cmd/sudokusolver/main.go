@@ -2,7 +2,10 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
@@ -18,16 +21,53 @@ var (
 	isCNFMode    bool
 	isSolveMode  bool
 	isManyMode   bool
+	isCountMode  bool
+	isUniqueMode bool
+	isAllMode    bool
+	isExplain    bool
+	isRate       bool
+	showCages    bool
+	isX          bool
+	isHyper      bool
+	isAntiKnight bool
+	isAntiKing   bool
+	isNonConsec  bool
+	isLatin      bool
+	limit        int
+	format       string
 	cpuprofile   string
 	memprofile   string
 	customSolver string
+	encoding     string
+	atMostOne    string
+	commanderGrp int
+	bimanderGrp  int
+	opts         sudokusolver.EncoderOptions
 )
 
 func init() {
 	flag.BoolVar(&isCNFMode, "cnf", false, "Generate CNF")
 	flag.BoolVar(&isSolveMode, "solve", true, "Solve with SAT solver")
 	flag.BoolVar(&isManyMode, "many", false, "Solve many one-line 9x9 sudoku w/ gophersat")
-	flag.StringVar(&customSolver, "solver", "gophersat", "Solve with specified SAT solver [implies -solve if set]")
+	flag.BoolVar(&isCountMode, "count", false, "Count the solutions (up to -limit)")
+	flag.BoolVar(&isUniqueMode, "unique", false, "Check that the sudoku has exactly one solution, exiting with 1 if it has more and 2 if it has none")
+	flag.BoolVar(&isAllMode, "all", false, "Print all solutions (up to -limit) in one-line format")
+	flag.BoolVar(&isExplain, "explain", false, "Explain the solution step by step, noting where search was needed")
+	flag.BoolVar(&isRate, "rate", false, "Rate the difficulty, also for each puzzle with -many")
+	flag.StringVar(&format, "format", "text", "Output format of -explain and -rate: text or json")
+	flag.BoolVar(&showCages, "cages", false, "Print the killer cages before the solution")
+	flag.BoolVar(&isX, "x", false, "Both diagonals hold distinct values (Sudoku-X), same as a diagonals line in the input")
+	flag.BoolVar(&isHyper, "hyper", false, "Add the Hyper Sudoku windows, same as a windows line in the input")
+	flag.BoolVar(&isAntiKnight, "antiknight", false, "Forbid equal values a knight's move apart, same as an antiknight line in the input")
+	flag.BoolVar(&isAntiKing, "antiking", false, "Forbid equal values a king's move apart, same as an antiking line in the input")
+	flag.BoolVar(&isNonConsec, "nonconsecutive", false, "Forbid consecutive values in adjacent cells, same as a nonconsecutive line in the input")
+	flag.BoolVar(&isLatin, "latin", false, "Latin square completion without boxes, for any size, same as a latin line in the input")
+	flag.IntVar(&limit, "limit", 0, "Maximum number of solutions to look for, 0 for no limit")
+	flag.StringVar(&customSolver, "solver", "gophersat", "Solve with specified SAT solver, or cdcl for the native solver [implies -solve if set]")
+	flag.StringVar(&encoding, "encoding", "range", "House constraint encoding: range or triad, which needs regular boxes")
+	flag.StringVar(&atMostOne, "amo", "auto", "At-most-one encoding: auto, pairwise, commander, bimander, binary, sequential, ladder or product")
+	flag.IntVar(&commanderGrp, "commander-group", sudokusolver.COMMANDER_FACTOR, "Group size of the commander at-most-one encoding")
+	flag.IntVar(&bimanderGrp, "bimander-group", sudokusolver.BIMANDER_FACTOR, "Group size of the bimander at-most-one encoding")
 	flag.StringVar(&cpuprofile, "cpuprofile", "", "Write CPU profile to a file")
 	flag.StringVar(&memprofile, "memprofile", "", "Write memory profile to a file")
 	flag.Parse()
@@ -43,6 +83,21 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	enc, err := sudokusolver.ParseEncoding(encoding)
+	if err != nil {
+		log.Fatal(err)
+	}
+	amo, err := sudokusolver.ParseAtMostOneEncoding(atMostOne)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts = sudokusolver.EncoderOptions{
+		Encoding:           enc,
+		AtMostOne:          amo,
+		CommanderGroupSize: commanderGrp,
+		BimanderGroupSize:  bimanderGrp,
+	}
+
 	mode := "solve"
 	if isCNFMode {
 		mode = "cnf"
@@ -50,14 +105,46 @@ func main() {
 	if !isCNFMode && customSolver != "gophersat" {
 		mode = "custom"
 	}
+	if !isCNFMode && customSolver == "cdcl" {
+		mode = "cdcl"
+	}
+	if isCountMode {
+		mode = "count"
+	}
+	if isUniqueMode {
+		mode = "unique"
+	}
+	if isAllMode {
+		mode = "all"
+	}
+	if isExplain {
+		mode = "explain"
+	}
+	if isRate {
+		mode = "rate"
+	}
 
 	if isManyMode {
+		if mode != "solve" && mode != "cdcl" && mode != "rate" {
+			log.Fatalf("%s mode isn't supported with -many", mode)
+		}
+		if names, _ := variants(); len(names) > 0 {
+			log.Fatalf("-%s isn't supported with -many, which only reads plain 9x9 sudoku", names[0])
+		}
 		// sudokusolver.SolveManyGophersat(os.Stdin, os.Stdout)
-		sudokusolver.SolveManyGini(os.Stdin, os.Stdout)
+		solveMany := sudokusolver.SolveManyGini
+		if mode == "cdcl" {
+			solveMany = sudokusolver.SolveManyCDCL
+		}
+		if mode == "rate" {
+			solveMany = sudokusolver.RateMany
+		}
+		if _, err := solveMany(os.Stdin, os.Stdout, opts); err != nil {
+			log.Println(err)
+		}
 	} else {
 		bytes, _ := ioutil.ReadAll(os.Stdin)
-		input := string(bytes)
-		solve(mode, input)
+		solve(mode, string(bytes))
 	}
 
 	if memprofile != "" {
@@ -74,24 +161,200 @@ func main() {
 }
 
 func solve(mode, input string) {
-	board := sudoku.NewFromString(input)
+	if sudoku.IsMulti(input) {
+		solveMulti(mode, input)
+		return
+	}
+
+	_, setup := variants()
+	board, err := sudoku.ParseWith(input, setup...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := opts.Validate(board); err != nil {
+		log.Fatal(err)
+	}
 
 	if mode == "cnf" {
 		g := gini.New()
-		cnf := sudokusolver.GenerateCNFConstraints(board, g)
+		cnf := sudokusolver.GenerateCNFConstraints(board, sudokusolver.GiniSink(g), opts)
+		writer := bufio.NewWriter(os.Stdout)
+		if err := cnf.Print(writer); err != nil {
+			log.Fatal(err)
+		}
+		writer.Flush()
+		return
+	}
+
+	if mode == "count" {
+		count, err := sudokusolver.CountSolutions(board, limit, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(count)
+		return
+	}
+
+	if mode == "unique" {
+		count, err := sudokusolver.CountSolutions(board, 2, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch count {
+		case 0:
+			fmt.Println("no solution")
+			os.Exit(2)
+		case 2:
+			fmt.Println("not unique")
+			os.Exit(1)
+		}
+		fmt.Println("unique")
+		board.Print(os.Stdout)
+		return
+	}
+
+	if mode == "all" {
+		solutions, errc := sudokusolver.EnumerateSolutions(context.Background(), board, limit, opts)
 		writer := bufio.NewWriter(os.Stdout)
-		cnf.Print(writer)
+		for solution := range solutions {
+			solution.PrintOneLine(writer)
+		}
 		writer.Flush()
+		if err := <-errc; err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if mode == "explain" {
+		explain(board)
+		return
+	}
+
+	if mode == "rate" {
+		rating, err := sudokusolver.Rate(board, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch format {
+		case "json":
+			err = json.NewEncoder(os.Stdout).Encode(rating)
+		case "text":
+			fmt.Println(rating)
+		default:
+			log.Fatalf("unknown format %q", format)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
 		return
 	}
 
 	if mode == "solve" {
-		sudokusolver.SolveWithGini(board)
+		_, err = sudokusolver.SolveWithGini(board, opts)
+	}
+
+	if mode == "cdcl" {
+		_, err = sudokusolver.SolveWithCDCL(board, opts)
 	}
 
 	if mode == "custom" {
-		sudokusolver.SolveWithCustomSolver(board, customSolver)
+		_, err = sudokusolver.SolveWithCustomSolver(board, customSolver, opts)
 	}
 
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if showCages {
+		board.PrintCages(os.Stdout)
+		fmt.Println()
+	}
 	board.Print(os.Stdout)
 }
+
+func explain(board *sudoku.Board) {
+	path, err := sudokusolver.Explain(board, opts)
+	switch format {
+	case "json":
+		out := struct {
+			Steps  []sudoku.Step `json:"steps"`
+			Search bool          `json:"search"`
+		}{path.Steps, path.Searched()}
+		if err := json.NewEncoder(os.Stdout).Encode(out); err != nil {
+			log.Fatal(err)
+		}
+	case "text":
+		path.Print(os.Stdout)
+		if path.Searched() {
+			fmt.Println("the last step was found by search")
+		}
+	default:
+		log.Fatalf("unknown format %q", format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if format == "text" {
+		fmt.Println()
+		board.Print(os.Stdout)
+	}
+}
+
+// variants returns the names of the variant flags set and the board calls
+// adding them.
+func variants() ([]string, []func(*sudoku.Board) error) {
+	var names []string
+	var setup []func(*sudoku.Board) error
+	for _, v := range []struct {
+		set   bool
+		name  string
+		apply func(*sudoku.Board) error
+	}{
+		{isLatin, "latin", (*sudoku.Board).RemoveBoxes},
+		{isX, "x", (*sudoku.Board).AddDiagonals},
+		{isHyper, "hyper", (*sudoku.Board).AddWindows},
+		{isAntiKnight, "antiknight", (*sudoku.Board).AddAntiKnight},
+		{isAntiKing, "antiking", (*sudoku.Board).AddAntiKing},
+		{isNonConsec, "nonconsecutive", (*sudoku.Board).AddNonConsecutive},
+	} {
+		if v.set {
+			names = append(names, v.name)
+			setup = append(setup, v.apply)
+		}
+	}
+	return names, setup
+}
+
+func solveMulti(mode, input string) {
+	if names, _ := variants(); len(names) > 0 {
+		log.Fatalf("-%s isn't supported for multi-grid puzzles", names[0])
+	}
+	m, err := sudoku.ParseMulti(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch mode {
+	case "cnf":
+		g := gini.New()
+		cnf := sudokusolver.GenerateMultiCNFConstraints(m, sudokusolver.GiniSink(g), opts)
+		writer := bufio.NewWriter(os.Stdout)
+		if err := cnf.Print(writer); err != nil {
+			log.Fatal(err)
+		}
+		writer.Flush()
+		return
+	case "solve":
+		_, err = sudokusolver.SolveMultiWithGini(m, opts)
+	case "cdcl":
+		_, err = sudokusolver.SolveMultiWithCDCL(m, opts)
+	default:
+		log.Fatalf("%s mode isn't supported for multi-grid puzzles", mode)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	m.Print(os.Stdout)
+}
@@ -0,0 +1,263 @@
+package sudoku
+
+import "sort"
+
+// The chain techniques work on a graph of candidates, numbered as nodes
+// idx*Size2 + value - 1.
+
+func (b *Board) node(idx, v int) int {
+	return idx*b.Size2 + v - 1
+}
+
+func (b *Board) nodeCandidate(n int) Candidate {
+	return b.candidate(n/b.Size2, n%b.Size2+1)
+}
+
+// strongLinks maps the candidates to those they're strongly linked with:
+// the other position of their value in a house with only two of them and,
+// unless the links are for a single value, the other candidate of their
+// cell if it only has two.
+func (b *Board) strongLinks(value int) map[int][]int {
+	links := make(map[int][]int)
+	link := func(n, m int) {
+		if !contains(links[n], m) {
+			links[n] = append(links[n], m)
+			links[m] = append(links[m], n)
+		}
+	}
+	for _, house := range b.allHouses() {
+		for v := 1; v <= b.Size2; v++ {
+			if value != 0 && v != value {
+				continue
+			}
+			if pair := b.positions(house, v); len(pair) == 2 {
+				link(b.node(pair[0], v), b.node(pair[1], v))
+			}
+		}
+	}
+	if value == 0 {
+		for _, idx := range b.bivalueCells() {
+			values := b.candidateValues(idx)
+			link(b.node(idx, values[0]), b.node(idx, values[1]))
+		}
+	}
+	return links
+}
+
+// weakLink reports whether two different candidates can't both be true.
+func (b *Board) weakLink(n, m int) bool {
+	if n/b.Size2 == m/b.Size2 {
+		return n != m
+	}
+	return n%b.Size2 == m%b.Size2 && b.sees(n/b.Size2, m/b.Size2)
+}
+
+// weakTargets lists the candidates on the board weakly linked with n: the
+// other candidates of its cell and its value in the cells it sees.
+func (b *Board) weakTargets(n int) []int {
+	cell, value := n/b.Size2, n%b.Size2+1
+	var targets []int
+	for idx := range b.Lookup {
+		if idx == cell {
+			for _, v := range b.candidateValues(idx) {
+				if v != value {
+					targets = append(targets, b.node(idx, v))
+				}
+			}
+		} else if b.isCandidate(idx, value) && b.sees(cell, idx) {
+			targets = append(targets, b.node(idx, value))
+		}
+	}
+	return targets
+}
+
+func findXChain(b *Board) *Step {
+	for v := 1; v <= b.Size2; v++ {
+		if step := b.findChain(XChain, b.strongLinks(v)); step != nil {
+			return step
+		}
+	}
+	return nil
+}
+
+func findAIC(b *Board) *Step {
+	return b.findChain(AIC, b.strongLinks(0))
+}
+
+// findChain finds the shortest alternating chain starting and ending with a
+// strong link that eliminates something: one of its ends is true, so a
+// candidate weakly linked with both is false.
+func (b *Board) findChain(technique Technique, links map[int][]int) *Step {
+	nodes := sortedNodes(links)
+	weak := make(map[int][]int, len(nodes))
+	for _, n := range nodes {
+		for _, m := range nodes {
+			if b.weakLink(n, m) {
+				weak[n] = append(weak[n], m)
+			}
+		}
+	}
+
+	var best *Step
+	for _, start := range nodes {
+		targets := b.weakTargets(start)
+		if len(targets) == 0 {
+			continue
+		}
+
+		// A state is a node times 2, plus 1 if it's reached by a strong
+		// link and the chain can end there.
+		parent := map[int]int{2 * start: -1}
+		depth := map[int]int{2 * start: 1}
+		queue := []int{2 * start}
+		for len(queue) > 0 {
+			state := queue[0]
+			queue = queue[1:]
+			if best != nil && depth[state]+1 >= len(best.Chain) {
+				break
+			}
+			n, strong := state/2, state%2 == 0
+			next := weak[n]
+			if strong {
+				next = links[n]
+			}
+			for _, m := range next {
+				nextState := 2 * m
+				if strong {
+					nextState++
+				}
+				if _, ok := parent[nextState]; ok {
+					continue
+				}
+				parent[nextState] = state
+				depth[nextState] = depth[state] + 1
+				queue = append(queue, nextState)
+				if !strong || m == start {
+					continue
+				}
+
+				var eliminations []Candidate
+				for _, z := range targets {
+					if z != m && b.weakLink(z, m) {
+						eliminations = append(eliminations, b.nodeCandidate(z))
+					}
+				}
+				if len(eliminations) > 0 {
+					var chain []int
+					for s := nextState; s != -1; s = parent[s] {
+						chain = append([]int{s / 2}, chain...)
+					}
+					best = b.chainStep(technique, chain, eliminations)
+					queue = nil
+					break
+				}
+			}
+		}
+	}
+	return best
+}
+
+// findSimpleColoring colors the candidates for a value linked by strong
+// links with two alternating colors, one of which is true. If two
+// candidates of the same color see each other, that color is false, and a
+// candidate seeing both colors is false.
+func findSimpleColoring(b *Board) *Step {
+	for v := 1; v <= b.Size2; v++ {
+		links := b.strongLinks(v)
+		colors := make(map[int]int)
+		for _, root := range sortedNodes(links) {
+			if _, ok := colors[root]; ok {
+				continue
+			}
+			colors[root] = 0
+			component := []int{root}
+			for i := 0; i < len(component); i++ {
+				for _, m := range links[component[i]] {
+					if _, ok := colors[m]; !ok {
+						colors[m] = 1 - colors[component[i]]
+						component = append(component, m)
+					}
+				}
+			}
+			sort.Ints(component)
+
+			for i, n := range component {
+				for _, m := range component[i+1:] {
+					if colors[n] != colors[m] || !b.weakLink(n, m) {
+						continue
+					}
+					var eliminations []Candidate
+					for _, z := range component {
+						if colors[z] == colors[n] {
+							eliminations = append(eliminations, b.nodeCandidate(z))
+						}
+					}
+					return b.chainStep(SimpleColoring, linkPath(links, n, m), eliminations)
+				}
+			}
+
+			for i, n := range component {
+				targets := b.weakTargets(n)
+				for _, m := range component[i+1:] {
+					if colors[n] == colors[m] {
+						continue
+					}
+					var eliminations []Candidate
+					for _, z := range targets {
+						if _, ok := colors[z]; !ok && b.weakLink(z, m) {
+							eliminations = append(eliminations, b.nodeCandidate(z))
+						}
+					}
+					if len(eliminations) > 0 {
+						return b.chainStep(SimpleColoring, linkPath(links, n, m), eliminations)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// chainStep makes the step of a chain of nodes.
+func (b *Board) chainStep(technique Technique, chain []int, eliminations []Candidate) *Step {
+	step := &Step{Technique: technique, Eliminations: eliminations}
+	for _, n := range chain {
+		c := b.nodeCandidate(n)
+		if len(step.Cells) == 0 || step.Cells[len(step.Cells)-1] != c.Cell {
+			step.Cells = append(step.Cells, c.Cell)
+		}
+		if !contains(step.Values, c.Value) {
+			step.Values = append(step.Values, c.Value)
+		}
+		step.Chain = append(step.Chain, c)
+	}
+	sort.Ints(step.Values)
+	return step
+}
+
+// linkPath returns the shortest path of links from one node to another.
+func linkPath(links map[int][]int, from, to int) []int {
+	parent := map[int]int{from: -1}
+	for queue := []int{from}; len(queue) > 0; queue = queue[1:] {
+		for _, m := range links[queue[0]] {
+			if _, ok := parent[m]; !ok {
+				parent[m] = queue[0]
+				queue = append(queue, m)
+			}
+		}
+	}
+	var path []int
+	for n := to; n != -1; n = parent[n] {
+		path = append([]int{n}, path...)
+	}
+	return path
+}
+
+func sortedNodes(links map[int][]int) []int {
+	nodes := make([]int, 0, len(links))
+	for n := range links {
+		nodes = append(nodes, n)
+	}
+	sort.Ints(nodes)
+	return nodes
+}
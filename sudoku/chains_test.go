@@ -0,0 +1,67 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// keepOnlyInCol removes v from every cell of the column but the given rows.
+func keepOnlyInCol(s *Board, col, v int, rows ...int) {
+	for r := 0; r < s.Size2; r++ {
+		if !contains(rows, r) {
+			s.SetValueFalse(r, col, v)
+		}
+	}
+}
+
+func TestSimpleColoring(t *testing.T) {
+	s := New(3)
+	keepOnly(s, 0, 5, 0, 4)
+	keepOnlyInCol(s, 4, 5, 0, 4)
+	keepOnly(s, 4, 5, 1, 4)
+	step := s.FindStep()
+	assert.Equal(t, SimpleColoring, step.Technique)
+	assert.Equal(t, "simple coloring r1c1 r1c5 r5c5 r5c2 {5} (5)r1c1=(5)r1c5=(5)r5c5=(5)r5c2: r2c2<>5 r3c2<>5 r4c1<>5 r6c1<>5", step.String())
+
+	s = New(3)
+	keepOnly(s, 0, 5, 0, 4)
+	keepOnlyInCol(s, 4, 5, 0, 5)
+	keepOnly(s, 5, 5, 1, 4)
+	keepOnlyInCol(s, 1, 5, 1, 5)
+	step = findSimpleColoring(s)
+	assert.Len(t, step.Chain, 5)
+	assert.Contains(t, step.Eliminations, Candidate{Cell{0, 0}, 5})
+	assert.Contains(t, step.Eliminations, Candidate{Cell{1, 1}, 5})
+	assert.Len(t, step.Eliminations, 3)
+}
+
+func TestXChain(t *testing.T) {
+	s := New(3)
+	keepOnly(s, 0, 5, 0, 4)
+	keepOnlyInCol(s, 4, 5, 0, 4)
+	keepOnly(s, 4, 5, 1, 4)
+	step := findXChain(s)
+	assert.Equal(t, XChain, step.Technique)
+	assert.Len(t, step.Chain, 4)
+	assert.Contains(t, step.Eliminations, Candidate{Cell{1, 1}, 5})
+
+	step.Apply(s)
+	assert.False(t, s.Candidates[s.Lit(1, 1, 5)])
+}
+
+func TestAIC(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 0), []int{1, 2}))
+	assert.NoError(t, s.RestrictCell(s.Idx(8, 8), []int{1, 2}))
+	keepOnly(s, 4, 1, 0, 8)
+	step := findAIC(s)
+	assert.Equal(t, AIC, step.Technique)
+	assert.Equal(t, []int{1, 2}, step.Values)
+	assert.Len(t, step.Chain, 6)
+	assert.Contains(t, step.Eliminations, Candidate{Cell{0, 8}, 2})
+
+	step.Apply(s)
+	assert.False(t, s.Candidates[s.Lit(0, 8, 2)])
+	assert.False(t, s.Candidates[s.Lit(8, 0, 2)])
+}
@@ -0,0 +1,77 @@
+package sudoku
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+ParseDigitSet parses a set of values a cell is restricted to:
+e even
+o odd
+s small, up to Size2/2
+b big, above (Size2+1)/2
+[135] or [1,3,5] for the listed values, with commas for values above 9
+*/
+func (b *Board) ParseDigitSet(token string) ([]int, error) {
+	var set []int
+	keep := func(allowed func(v int) bool) []int {
+		for v := 1; v <= b.Size2; v++ {
+			if allowed(v) {
+				set = append(set, v)
+			}
+		}
+		return set
+	}
+
+	switch strings.ToLower(token) {
+	case "e":
+		return keep(func(v int) bool { return v%2 == 0 }), nil
+	case "o":
+		return keep(func(v int) bool { return v%2 == 1 }), nil
+	case "s":
+		return keep(func(v int) bool { return v <= b.Size2/2 }), nil
+	case "b":
+		return keep(func(v int) bool { return v > (b.Size2+1)/2 }), nil
+	}
+
+	if len(token) < 3 || token[0] != '[' || token[len(token)-1] != ']' {
+		return nil, fmt.Errorf("invalid cell %q", token)
+	}
+	values := strings.Split(token[1:len(token)-1], ",")
+	if len(values) == 1 {
+		values = strings.Split(values[0], "")
+	}
+	for _, value := range values {
+		v, err := strconv.Atoi(value)
+		if err != nil || v < 1 || v > b.Size2 {
+			return nil, fmt.Errorf("invalid value %q in %q", value, token)
+		}
+		set = append(set, v)
+	}
+	return set, nil
+}
+
+// RestrictCell removes the candidates of the cell idx that aren't in values.
+func (b *Board) RestrictCell(idx int, values []int) error {
+	if idx < 0 || idx >= len(b.Lookup) {
+		return fmt.Errorf("cell %d out of range", idx)
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("%s can't hold any value", b.CellName(idx))
+	}
+	allowed := make([]bool, b.Size2+1)
+	for _, v := range values {
+		if v < 1 || v > b.Size2 {
+			return fmt.Errorf("%s: value %d out of range", b.CellName(idx), v)
+		}
+		allowed[v] = true
+	}
+	for v := 1; v <= b.Size2; v++ {
+		if !allowed[v] {
+			b.SetValueFalse(idx/b.Size2, idx%b.Size2, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDigitSet(t *testing.T) {
+	s := New(3)
+	for token, expected := range map[string][]int{
+		"e":       {2, 4, 6, 8},
+		"O":       {1, 3, 5, 7, 9},
+		"s":       {1, 2, 3, 4},
+		"b":       {6, 7, 8, 9},
+		"[135]":   {1, 3, 5},
+		"[2,9]":   {2, 9},
+		"[7]":     {7},
+		"[1,2,3]": {1, 2, 3},
+	} {
+		set, err := s.ParseDigitSet(token)
+		assert.NoError(t, err, token)
+		assert.Equal(t, expected, set, token)
+	}
+	for _, token := range []string{"x", "[]", "[1,10]", "[0]", "135]"} {
+		_, err := s.ParseDigitSet(token)
+		assert.Error(t, err, token)
+	}
+
+	big := New(4)
+	set, err := big.ParseDigitSet("[1,12,16]")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 12, 16}, set)
+	set, _ = big.ParseDigitSet("b")
+	assert.Equal(t, []int{9, 10, 11, 12, 13, 14, 15, 16}, set)
+}
+
+func TestRestrictCell(t *testing.T) {
+	s := New(2)
+	numCandidates := s.NumCandidates
+	assert.Error(t, s.RestrictCell(0, nil))
+	assert.Error(t, s.RestrictCell(0, []int{5}))
+	assert.NoError(t, s.RestrictCell(s.Idx(1, 2), []int{1, 3}))
+	assert.Equal(t, numCandidates-2, s.NumCandidates)
+	assert.False(t, s.Candidates[s.Lit(1, 2, 2)])
+	assert.True(t, s.Candidates[s.Lit(1, 2, 3)])
+}
+
+func TestParseDigitSets(t *testing.T) {
+	s, err := Parse("e 0 0 0\n0 [13] 0 0\n0 0 0 0\n0 0 0 o")
+	assert.NoError(t, err)
+	assert.False(t, s.Candidates[s.Lit(0, 0, 1)])
+	assert.True(t, s.Candidates[s.Lit(0, 0, 4)])
+	assert.False(t, s.Candidates[s.Lit(1, 1, 2)])
+	assert.False(t, s.Candidates[s.Lit(3, 3, 4)])
+
+	s, err = Parse("e[13]3" + strings.Repeat(".", 77) + "o")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, s.Lookup[2])
+	assert.False(t, s.Candidates[s.Lit(0, 0, 3)])
+	assert.False(t, s.Candidates[s.Lit(0, 1, 2)])
+	assert.False(t, s.Candidates[s.Lit(8, 8, 8)])
+
+	_, err = Parse("e[13" + strings.Repeat(".", 79))
+	assert.Error(t, err)
+	_, err = Parse("x 0 0 0\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+	_, err = Parse("1 0 0 0\n0 0 0 0\n0 0 0 0\n0 0 0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package sudoku
+
+var fishes = []Technique{2: XWing, 3: Swordfish, 4: Jellyfish}
+
+// fishFinder finds n rows whose candidates for a value all lie in the same n
+// columns, so the value can't be anywhere else in those columns, or the same
+// with columns and rows swapped.
+func fishFinder(n int) func(b *Board) *Step {
+	return func(b *Board) *Step {
+		for v := 1; v <= b.Size2; v++ {
+			if step := b.findFish(n, v, b.rowCandidateCount, func(line, i int) int { return b.Idx(line, i) }); step != nil {
+				return step
+			}
+			if step := b.findFish(n, v, b.colCandidateCount, func(line, i int) int { return b.Idx(i, line) }); step != nil {
+				return step
+			}
+		}
+		return nil
+	}
+}
+
+// findFish looks for a fish with base lines given by at(line, i), the i-th
+// cell of a line, and cover lines crossing them.
+func (b *Board) findFish(n, v int, counts []int, at func(line, i int) int) *Step {
+	var base []int
+	var crossing [][]int
+	for line := 0; line < b.Size2; line++ {
+		if count := counts[line*b.Size2+v-1]; count >= 2 && count <= n && !b.placedIn(line, v, at) {
+			var cover []int
+			for i := 0; i < b.Size2; i++ {
+				if b.isCandidate(at(line, i), v) {
+					cover = append(cover, i)
+				}
+			}
+			base = append(base, line)
+			crossing = append(crossing, cover)
+		}
+	}
+
+	var step *Step
+	lockedSets(base, crossing, n, func(lines, covers []int) bool {
+		var cells []int
+		for _, line := range lines {
+			for _, i := range covers {
+				if b.isCandidate(at(line, i), v) {
+					cells = append(cells, at(line, i))
+				}
+			}
+		}
+
+		var eliminations []Candidate
+		for _, i := range covers {
+			for line := 0; line < b.Size2; line++ {
+				if !contains(lines, line) && b.isCandidate(at(line, i), v) {
+					eliminations = append(eliminations, b.candidate(at(line, i), v))
+				}
+			}
+		}
+		if len(eliminations) == 0 {
+			return false
+		}
+		step = &Step{
+			Technique:    fishes[n],
+			Cells:        b.cells(cells),
+			Values:       []int{v},
+			Eliminations: eliminations,
+		}
+		return true
+	})
+	return step
+}
+
+func (b *Board) placedIn(line, v int, at func(line, i int) int) bool {
+	for i := 0; i < b.Size2; i++ {
+		if b.Lookup[at(line, i)] == v {
+			return true
+		}
+	}
+	return false
+}
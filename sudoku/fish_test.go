@@ -0,0 +1,77 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// keepOnly removes v from every cell of the row but the given columns.
+func keepOnly(s *Board, row, v int, cols ...int) {
+	for c := 0; c < s.Size2; c++ {
+		if !contains(cols, c) {
+			s.SetValueFalse(row, c, v)
+		}
+	}
+}
+
+func TestXWing(t *testing.T) {
+	s := New(3)
+	keepOnly(s, 0, 4, 1, 6)
+	keepOnly(s, 4, 4, 1, 6)
+	step := s.FindStep()
+	assert.Equal(t, XWing, step.Technique)
+	assert.Equal(t, []Cell{{0, 1}, {0, 6}, {4, 1}, {4, 6}}, step.Cells)
+	assert.Equal(t, []int{4}, step.Values)
+	assert.Len(t, step.Eliminations, 14)
+	assert.Contains(t, step.Eliminations, Candidate{Cell{8, 6}, 4})
+
+	s = New(2)
+	for r := 0; r < 4; r++ {
+		if r != 0 && r != 3 {
+			s.SetValueFalse(r, 0, 2)
+			s.SetValueFalse(r, 3, 2)
+		}
+	}
+	step = fishFinder(2)(s)
+	assert.Equal(t, "x-wing r2c2 r2c3 r3c2 r3c3 {2}: r1c2<>2 r4c2<>2 r1c3<>2 r4c3<>2", step.String())
+}
+
+func TestSwordfish(t *testing.T) {
+	s := New(3)
+	keepOnly(s, 0, 4, 0, 3)
+	keepOnly(s, 4, 4, 3, 6)
+	keepOnly(s, 8, 4, 0, 6)
+	assert.Nil(t, fishFinder(2)(s))
+	step := fishFinder(3)(s)
+	assert.Equal(t, Swordfish, step.Technique)
+	assert.Len(t, step.Cells, 6)
+	assert.Len(t, step.Eliminations, 18)
+
+	step.Apply(s)
+	assert.Nil(t, fishFinder(3)(s))
+}
+
+func TestJellyfish(t *testing.T) {
+	s := New(4)
+	keepOnly(s, 0, 9, 0, 5)
+	keepOnly(s, 5, 9, 5, 10)
+	keepOnly(s, 10, 9, 10, 15)
+	keepOnly(s, 15, 9, 15, 0)
+	step := fishFinder(4)(s)
+	assert.Equal(t, Jellyfish, step.Technique)
+	assert.Len(t, step.Cells, 8)
+	assert.Len(t, step.Eliminations, 48)
+}
+
+func TestJellyfishLargeBoard(t *testing.T) {
+	s := New(10)
+	keepOnly(s, 0, 9, 0, 15)
+	keepOnly(s, 15, 9, 15, 30)
+	keepOnly(s, 30, 9, 30, 45)
+	keepOnly(s, 45, 9, 45, 0)
+	step := fishFinder(4)(s)
+	assert.Equal(t, Jellyfish, step.Technique)
+	assert.Len(t, step.Cells, 8)
+	assert.Len(t, step.Eliminations, 4*96)
+}
@@ -0,0 +1,63 @@
+package sudoku
+
+// NextHint returns the simplest deduction that can be made next on the
+// board, placements or eliminations with the technique and the cells
+// justifying them, without changing the board. It's nil if there's nothing
+// left to deduce, because the board is solved or only search can go on.
+func NextHint(b *Board) *Step {
+	return b.FindStep()
+}
+
+// findPairRelation finds a pair constraint ruling out candidates of one of
+// its cells, that no candidate of the other cell supports.
+func (b *Board) findPairRelation() *Step {
+	for _, p := range b.PairConstraints {
+		var eliminations []Candidate
+		for v := 1; v <= b.Size2; v++ {
+			if b.isCandidate(p.A, v) && !b.hasSupport(p.B, func(w int) bool { return p.Relation(v, w) }) {
+				eliminations = append(eliminations, b.candidate(p.A, v))
+			}
+		}
+		for v := 1; v <= b.Size2; v++ {
+			if b.isCandidate(p.B, v) && !b.hasSupport(p.A, func(w int) bool { return p.Relation(w, v) }) {
+				eliminations = append(eliminations, b.candidate(p.B, v))
+			}
+		}
+		if len(eliminations) > 0 {
+			return &Step{
+				Technique:    PairRelation,
+				Cells:        b.cells([]int{p.A, p.B}),
+				Eliminations: eliminations,
+			}
+		}
+	}
+	return nil
+}
+
+// findInequalityBound finds an inequality ruling out the candidates of its
+// greater cell up to the smallest of the less one, or the other way around.
+func (b *Board) findInequalityBound() *Step {
+	for _, ineq := range b.Inequalities {
+		lowest, _ := b.candidateBounds(ineq.Less)
+		_, highest := b.candidateBounds(ineq.Greater)
+		var eliminations []Candidate
+		for v := 1; v <= b.Size2; v++ {
+			if v >= highest && b.isCandidate(ineq.Less, v) {
+				eliminations = append(eliminations, b.candidate(ineq.Less, v))
+			}
+		}
+		for v := 1; v <= b.Size2; v++ {
+			if v <= lowest && b.isCandidate(ineq.Greater, v) {
+				eliminations = append(eliminations, b.candidate(ineq.Greater, v))
+			}
+		}
+		if len(eliminations) > 0 {
+			return &Step{
+				Technique:    InequalityBound,
+				Cells:        b.cells([]int{ineq.Less, ineq.Greater}),
+				Eliminations: eliminations,
+			}
+		}
+	}
+	return nil
+}
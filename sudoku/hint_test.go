@@ -0,0 +1,47 @@
+package sudoku
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextHint(t *testing.T) {
+	input, _ := ioutil.ReadFile("../data/sudoku-hyper-9-1.txt")
+	s := NewFromString(string(input))
+	candidates := append([]bool(nil), s.Candidates...)
+	hint := NextHint(s)
+	assert.Equal(t, "hidden single r3c3 {5}: r3c3=5", hint.String())
+	assert.Equal(t, candidates, s.Candidates)
+	assert.Equal(t, 0, s.Lookup[s.Idx(2, 2)])
+
+	for hint != nil {
+		hint.Apply(s)
+		hint = NextHint(s)
+	}
+	assert.True(t, s.Solved())
+}
+
+func TestNextHintConstraints(t *testing.T) {
+	input, _ := ioutil.ReadFile("../data/sudoku-kropki-9-1.txt")
+	s := NewFromString(string(input))
+	hint := NextHint(s)
+	assert.Equal(t, "pair relation r1c1 r1c2: r1c1<>5 r1c1<>9 r1c2<>5 r1c2<>7 r1c2<>9", hint.String())
+	assert.True(t, s.Candidates[s.Lit(0, 0, 5)])
+
+	s = NewLatin(4)
+	assert.NoError(t, s.AddInequality(s.Idx(0, 0), s.Idx(0, 1)))
+	s.SetValueFalse(0, 0, 1)
+	hint = NextHint(s)
+	assert.Equal(t, InequalityBound, hint.Technique)
+	assert.Equal(t, []Cell{{0, 0}, {0, 1}}, hint.Cells)
+	assert.Equal(t, []Candidate{{Cell{0, 1}, 2}}, hint.Eliminations)
+
+	s = NewLatin(2)
+	s.SetValue(0, 0, 1)
+	s.SetValue(0, 1, 2)
+	s.SetValue(1, 0, 2)
+	s.SetValue(1, 1, 1)
+	assert.Nil(t, NextHint(s))
+}
@@ -0,0 +1,85 @@
+package sudoku
+
+import "fmt"
+
+// AddHouse adds an extra house: Size2 cells (idx) holding every value exactly
+// once, on top of the rows, columns and blocks. Values already set are
+// eliminated from the rest of the house.
+func (b *Board) AddHouse(cells []int) error {
+	if len(cells) != b.Size2 {
+		return fmt.Errorf("house has %d cells, expected %d", len(cells), b.Size2)
+	}
+	seen := make(map[int]bool, len(cells))
+	for _, idx := range cells {
+		if idx < 0 || idx >= len(b.Lookup) {
+			return fmt.Errorf("cell %d out of range", idx)
+		}
+		if seen[idx] {
+			return fmt.Errorf("%s appears twice in the house", b.CellName(idx))
+		}
+		seen[idx] = true
+	}
+
+	if b.houseIdxMap == nil {
+		b.houseIdxMap = make([][]int, len(b.Lookup))
+	}
+	house := len(b.Houses)
+	b.Houses = append(b.Houses, append([]int(nil), cells...))
+	for _, idx := range cells {
+		b.houseIdxMap[idx] = append(b.houseIdxMap[idx], house)
+	}
+
+	counts := make([]int, b.Size2)
+	for _, idx := range cells {
+		for v := 1; v <= b.Size2; v++ {
+			if b.Candidates[b.Lit(idx/b.Size2, idx%b.Size2, v)] {
+				counts[v-1]++
+			}
+		}
+	}
+	b.houseCandidateCount = append(b.houseCandidateCount, counts...)
+
+	for _, idx := range cells {
+		if b.Lookup[idx] != 0 {
+			b.SetValue(idx/b.Size2, idx%b.Size2, b.Lookup[idx])
+		}
+	}
+
+	return nil
+}
+
+// AddDiagonals adds both main diagonals as houses, as in Sudoku-X.
+func (b *Board) AddDiagonals() error {
+	main := make([]int, b.Size2)
+	anti := make([]int, b.Size2)
+	for i := 0; i < b.Size2; i++ {
+		main[i] = b.Idx(i, i)
+		anti[i] = b.Idx(i, b.Size2-1-i)
+	}
+	if err := b.AddHouse(main); err != nil {
+		return err
+	}
+	return b.AddHouse(anti)
+}
+
+// AddWindows adds the extra Size x Size windows of Hyper Sudoku (Windoku), one
+// cell off the border and one cell apart, e.g. four windows for 9x9.
+func (b *Board) AddWindows() error {
+	if b.Size == 0 {
+		return fmt.Errorf("windows need square boxes")
+	}
+	for wr := 0; wr < b.Size-1; wr++ {
+		for wc := 0; wc < b.Size-1; wc++ {
+			cells := make([]int, 0, b.Size2)
+			for r := 0; r < b.Size; r++ {
+				for c := 0; c < b.Size; c++ {
+					cells = append(cells, b.Idx(1+wr*(b.Size+1)+r, 1+wc*(b.Size+1)+c))
+				}
+			}
+			if err := b.AddHouse(cells); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDiagonals(t *testing.T) {
+	s := New(3)
+	s.SetValue(0, 0, 5)
+	assert.NoError(t, s.AddDiagonals())
+	assert.Len(t, s.Houses, 2)
+
+	// eliminated from the main diagonal, both before and after adding it
+	assert.False(t, s.Candidates[s.Lit(8, 8, 5)])
+	s.SetValue(0, 8, 7)
+	assert.False(t, s.Candidates[s.Lit(4, 4, 7)])
+	assert.False(t, s.Candidates[s.Lit(8, 0, 7)])
+	assert.True(t, s.Candidates[s.Lit(8, 1, 7)])
+}
+
+func TestAddHouse(t *testing.T) {
+	s := New(2)
+	assert.Error(t, s.AddHouse([]int{0, 1, 2}))
+	assert.Error(t, s.AddHouse([]int{0, 1, 2, 2}))
+	assert.NoError(t, s.AddHouse([]int{0, 5, 10, 15}))
+}
+
+func TestHiddenSinglesInExtraHouse(t *testing.T) {
+	s := New(2)
+	assert.NoError(t, s.AddDiagonals())
+	// 1 can only go to r4c4 on the main diagonal
+	s.SetValue(0, 1, 1)
+	s.SetValue(1, 0, 2)
+	s.SetValueFalse(1, 1, 1)
+	s.SetValueFalse(2, 2, 1)
+
+	assert.True(t, s.HiddenSingles())
+	assert.Equal(t, 1, s.Lookup[s.Idx(3, 3)])
+}
+
+func TestAddWindows(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.AddWindows())
+	assert.Len(t, s.Houses, 4)
+	assert.Equal(t, []int{10, 11, 12, 19, 20, 21, 28, 29, 30}, s.Houses[0])
+	assert.Equal(t, []int{50, 51, 52, 59, 60, 61, 68, 69, 70}, s.Houses[3])
+
+	s.SetValue(1, 1, 4)
+	assert.False(t, s.Candidates[s.Lit(3, 3, 4)])
+	assert.True(t, s.Candidates[s.Lit(3, 4, 4)])
+
+	s = New(4)
+	assert.NoError(t, s.AddWindows())
+	assert.Len(t, s.Houses, 9)
+	assert.Equal(t, s.Idx(14, 14), s.Houses[8][15])
+
+	assert.Error(t, NewRect(2, 3).AddWindows())
+}
@@ -0,0 +1,59 @@
+package sudoku
+
+import "fmt"
+
+// Inequality requires the value of cell Less to be smaller than the value of
+// cell Greater (idx), as in greater-than sudoku and futoshiki.
+type Inequality struct {
+	Less, Greater int
+}
+
+// AddInequality requires less's value to be smaller than greater's.
+func (b *Board) AddInequality(less, greater int) error {
+	if less < 0 || less >= len(b.Lookup) || greater < 0 || greater >= len(b.Lookup) {
+		return fmt.Errorf("cell out of range")
+	}
+	if less == greater {
+		return fmt.Errorf("%s can't be compared to itself", b.CellName(less))
+	}
+	b.Inequalities = append(b.Inequalities, Inequality{Less: less, Greater: greater})
+	b.InequalityBounds()
+	return nil
+}
+
+// InequalityBounds removes the candidates of the smaller cells that aren't
+// below the largest candidate of the greater one and vice versa, returning
+// whether any was removed.
+func (b *Board) InequalityBounds() bool {
+	changed := false
+	for _, ineq := range b.Inequalities {
+		lowest, _ := b.candidateBounds(ineq.Less)
+		_, highest := b.candidateBounds(ineq.Greater)
+		for v := 1; v <= b.Size2; v++ {
+			if v <= lowest && b.Candidates[b.Lit(ineq.Greater/b.Size2, ineq.Greater%b.Size2, v)] {
+				b.SetValueFalse(ineq.Greater/b.Size2, ineq.Greater%b.Size2, v)
+				changed = true
+			}
+			if v >= highest && b.Candidates[b.Lit(ineq.Less/b.Size2, ineq.Less%b.Size2, v)] {
+				b.SetValueFalse(ineq.Less/b.Size2, ineq.Less%b.Size2, v)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// candidateBounds returns the smallest and largest candidates of idx, or
+// Size2+1 and 0 without any.
+func (b *Board) candidateBounds(idx int) (int, int) {
+	lowest, highest := b.Size2+1, 0
+	for v := 1; v <= b.Size2; v++ {
+		if b.Candidates[b.Lit(idx/b.Size2, idx%b.Size2, v)] {
+			if v < lowest {
+				lowest = v
+			}
+			highest = v
+		}
+	}
+	return lowest, highest
+}
@@ -0,0 +1,35 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddInequality(t *testing.T) {
+	s := New(2)
+	assert.Error(t, s.AddInequality(0, 0))
+	assert.NoError(t, s.AddInequality(s.Idx(0, 0), s.Idx(0, 1)))
+	assert.False(t, s.Candidates[s.Lit(0, 0, 4)])
+	assert.False(t, s.Candidates[s.Lit(0, 1, 1)])
+
+	assert.NoError(t, s.AddInequality(s.Idx(0, 1), s.Idx(1, 1)))
+	assert.False(t, s.Candidates[s.Lit(1, 1, 2)])
+	assert.True(t, s.Candidates[s.Lit(1, 1, 3)])
+
+	s.SetValue(1, 1, 3)
+	assert.True(t, s.InequalityBounds())
+	s.BasicSolve()
+	assert.Equal(t, 2, s.Lookup[s.Idx(0, 1)])
+	assert.Equal(t, 1, s.Lookup[s.Idx(0, 0)])
+}
+
+func TestRemoveBoxes(t *testing.T) {
+	s, err := Parse("latin\n1 0 0 0 0\n0 0 0 0 0\n0 0 0 0 0\n0 0 0 0 0\n0 0 0 0 0")
+	assert.NoError(t, err)
+	assert.False(t, s.HasBoxes())
+	assert.False(t, s.RegularBlocks())
+	assert.True(t, s.Candidates[s.Lit(1, 1, 1)])
+	assert.False(t, s.Candidates[s.Lit(0, 1, 1)])
+	assert.Error(t, s.RemoveBoxes())
+}
@@ -0,0 +1,97 @@
+package sudoku
+
+// findPointing finds a value whose candidates in a box are all in one row or
+// column, so it can't be anywhere else in that line.
+func findPointing(b *Board) *Step {
+	if !b.HasBoxes() {
+		return nil
+	}
+	for blk, box := range b.Blocks {
+		for v := 1; v <= b.Size2; v++ {
+			if b.blkCandidateCount[blk*b.Size2+v-1] < 2 {
+				continue
+			}
+			if step := b.lockedCandidates(Pointing, box, v, b.lines); step != nil {
+				return step
+			}
+		}
+	}
+	return nil
+}
+
+// findClaiming finds a value whose candidates in a row or column are all in
+// one box, so it can't be anywhere else in that box.
+func findClaiming(b *Board) *Step {
+	if !b.HasBoxes() {
+		return nil
+	}
+	box := func(idx int) [][]int {
+		return [][]int{b.Blocks[b.blkIdxMap[idx]]}
+	}
+	for i := 0; i < b.Size2; i++ {
+		row, col := b.lines(b.Idx(i, i))[0], b.lines(b.Idx(i, i))[1]
+		for v := 1; v <= b.Size2; v++ {
+			if b.rowCandidateCount[i*b.Size2+v-1] >= 2 {
+				if step := b.lockedCandidates(Claiming, row, v, box); step != nil {
+					return step
+				}
+			}
+			if b.colCandidateCount[i*b.Size2+v-1] >= 2 {
+				if step := b.lockedCandidates(Claiming, col, v, box); step != nil {
+					return step
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lockedCandidates checks whether the candidates for v in house all share
+// one of the houses returned by others, in which case v is removed from the
+// rest of that house.
+func (b *Board) lockedCandidates(technique Technique, house []int, v int, others func(idx int) [][]int) *Step {
+	cells := b.positions(house, v)
+	if len(cells) < 2 {
+		return nil
+	}
+	for _, other := range others(cells[0]) {
+		if !containsAll(other, cells) {
+			continue
+		}
+		var eliminations []Candidate
+		for _, idx := range other {
+			if !contains(house, idx) && b.isCandidate(idx, v) {
+				eliminations = append(eliminations, b.candidate(idx, v))
+			}
+		}
+		if len(eliminations) > 0 {
+			return &Step{
+				Technique:    technique,
+				Cells:        b.cells(cells),
+				Values:       []int{v},
+				Eliminations: eliminations,
+			}
+		}
+	}
+	return nil
+}
+
+// lines returns the row and the column of idx.
+func (b *Board) lines(idx int) [][]int {
+	row := make([]int, b.Size2)
+	col := make([]int, b.Size2)
+	for j := 0; j < b.Size2; j++ {
+		row[j] = b.Idx(idx/b.Size2, j)
+		col[j] = b.Idx(j, idx%b.Size2)
+	}
+	return [][]int{row, col}
+}
+
+func containsAll(items []int, subset []int) bool {
+	for _, item := range subset {
+		if !contains(items, item) {
+			return false
+		}
+	}
+	return true
+}
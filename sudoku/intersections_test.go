@@ -0,0 +1,44 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointing(t *testing.T) {
+	s := New(3)
+	for r := 1; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			s.SetValueFalse(r, c, 5)
+		}
+	}
+	step := s.FindStep()
+	assert.Equal(t, Pointing, step.Technique)
+	assert.Equal(t, []Cell{{0, 0}, {0, 1}, {0, 2}}, step.Cells)
+	assert.Equal(t, []int{5}, step.Values)
+	assert.Len(t, step.Eliminations, 6)
+	assert.Contains(t, step.Eliminations, Candidate{Cell{0, 8}, 5})
+
+	s = NewLatin(4)
+	s.SetValueFalse(0, 2, 1)
+	s.SetValueFalse(0, 3, 1)
+	assert.Nil(t, findPointing(s))
+	assert.Nil(t, findClaiming(s))
+}
+
+func TestClaiming(t *testing.T) {
+	s := New(3)
+	for c := 3; c < 9; c++ {
+		s.SetValueFalse(0, c, 5)
+	}
+	step := s.FindStep()
+	assert.Equal(t, Claiming, step.Technique)
+	assert.Equal(t, []Cell{{0, 0}, {0, 1}, {0, 2}}, step.Cells)
+	assert.Len(t, step.Eliminations, 6)
+	assert.Contains(t, step.Eliminations, Candidate{Cell{2, 2}, 5})
+	assert.Equal(t, "claiming r1c1 r1c2 r1c3 {5}: r2c1<>5", step.String()[:36])
+
+	step.Apply(s)
+	assert.Nil(t, findClaiming(s))
+}
@@ -0,0 +1,132 @@
+package sudoku
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Cage is a killer sudoku cage: its cells hold distinct values adding up to
+// Sum.
+type Cage struct {
+	Cells []int // idx
+	Sum   int
+}
+
+// AddCage adds a cage over cells (idx) and removes the candidates that can't
+// be part of any combination adding up to sum. Cages can't overlap.
+func (b *Board) AddCage(cells []int, sum int) error {
+	if len(cells) == 0 {
+		return fmt.Errorf("cage without cells")
+	}
+	if b.cageIdxMap == nil {
+		b.cageIdxMap = make([]int, b.Size2*b.Size2)
+		for i := range b.cageIdxMap {
+			b.cageIdxMap[i] = -1
+		}
+	}
+	for _, idx := range cells {
+		if idx < 0 || idx >= len(b.Lookup) {
+			return fmt.Errorf("cell %d out of range", idx)
+		}
+		if b.cageIdxMap[idx] != -1 {
+			return fmt.Errorf("%s is already in a cage", b.CellName(idx))
+		}
+	}
+
+	combinations := SumCombinations(len(cells), sum, b.Size2)
+	if len(combinations) == 0 {
+		return fmt.Errorf("no %d distinct values add up to %d", len(cells), sum)
+	}
+
+	cage := Cage{Cells: append([]int(nil), cells...), Sum: sum}
+	for _, idx := range cells {
+		b.cageIdxMap[idx] = len(b.Cages)
+	}
+	b.Cages = append(b.Cages, cage)
+
+	allowed := make([]bool, b.Size2+1)
+	for _, combination := range combinations {
+		for _, v := range combination {
+			allowed[v] = true
+		}
+	}
+	for _, idx := range cells {
+		for v := 1; v <= b.Size2; v++ {
+			if !allowed[v] {
+				b.SetValueFalse(idx/b.Size2, idx%b.Size2, v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SumCombinations lists the sets of count distinct values in 1..maxValue that
+// add up to sum, each in increasing order.
+func SumCombinations(count, sum, maxValue int) [][]int {
+	var combinations [][]int
+	combination := make([]int, 0, count)
+
+	var search func(from, left int)
+	search = func(from, left int) {
+		if len(combination) == count {
+			if left == 0 {
+				combinations = append(combinations, append([]int(nil), combination...))
+			}
+			return
+		}
+		for v := from; v <= maxValue && v <= left; v++ {
+			combination = append(combination, v)
+			search(v+1, left-v)
+			combination = combination[:len(combination)-1]
+		}
+	}
+	search(1, sum)
+
+	return combinations
+}
+
+// PrintCages prints a map of the cages, labelled a, b, ..., z, aa, ab, ...,
+// followed by the sum of each label. Cells outside any cage are shown as ".".
+func (b *Board) PrintCages(w io.Writer) {
+	labels := make([]string, len(b.Cages))
+	width := 1
+	for i := range b.Cages {
+		labels[i] = cageLabel(i)
+		if len(labels[i]) > width {
+			width = len(labels[i])
+		}
+	}
+
+	for r := 0; r < b.Size2; r++ {
+		for c := 0; c < b.Size2; c++ {
+			label := "."
+			if b.cageIdxMap != nil && b.cageIdxMap[b.Idx(r, c)] >= 0 {
+				label = labels[b.cageIdxMap[b.Idx(r, c)]]
+			}
+			sep := " "
+			if c == b.Size2-1 {
+				sep = "\n"
+			}
+			fmt.Fprintf(w, "%-*s%s", width, label, sep)
+		}
+	}
+
+	sums := make([]string, len(b.Cages))
+	for i, cage := range b.Cages {
+		sums[i] = fmt.Sprintf("%s=%d", labels[i], cage.Sum)
+	}
+	fmt.Fprintln(w, strings.Join(sums, " "))
+}
+
+func cageLabel(i int) string {
+	label := ""
+	for {
+		label = string(rune('a'+i%26)) + label
+		i = i/26 - 1
+		if i < 0 {
+			return label
+		}
+	}
+}
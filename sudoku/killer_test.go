@@ -0,0 +1,65 @@
+package sudoku
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumCombinations(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2}}, SumCombinations(2, 3, 9))
+	assert.Equal(t, [][]int{{1, 2, 4}}, SumCombinations(3, 7, 9))
+	assert.Equal(t, [][]int{{1, 9}, {2, 8}, {3, 7}, {4, 6}}, SumCombinations(2, 10, 9))
+	assert.Empty(t, SumCombinations(2, 18, 9))
+	assert.Len(t, SumCombinations(9, 45, 9), 1)
+}
+
+func TestAddCage(t *testing.T) {
+	b := New(3)
+	assert.NoError(t, b.AddCage([]int{b.Idx(0, 0), b.Idx(0, 1)}, 3))
+
+	for v := 3; v <= 9; v++ {
+		assert.False(t, b.Candidates[b.Lit(0, 0, v)])
+		assert.False(t, b.Candidates[b.Lit(0, 1, v)])
+	}
+	assert.True(t, b.Candidates[b.Lit(0, 0, 1)])
+	assert.True(t, b.Candidates[b.Lit(0, 1, 2)])
+
+	b.SetValue(0, 0, 1)
+	assert.False(t, b.Candidates[b.Lit(0, 1, 1)])
+
+	assert.Error(t, b.AddCage([]int{b.Idx(0, 1), b.Idx(0, 2)}, 5))
+	assert.Error(t, b.AddCage([]int{b.Idx(1, 1), b.Idx(1, 2)}, 18))
+	assert.Error(t, b.AddCage(nil, 5))
+}
+
+func TestParseCages(t *testing.T) {
+	input := `
+	# a cage in the corner
+	cage 3 r1c1 R1C2
+	0 0 0 0
+	0 0 0 0
+	0 0 0 0
+	0 0 0 0`
+
+	b, err := Parse(input)
+	assert.NoError(t, err)
+	assert.Equal(t, []Cage{{Cells: []int{0, 1}, Sum: 3}}, b.Cages)
+
+	var out bytes.Buffer
+	b.PrintCages(&out)
+	assert.Equal(t, "a a . .\n. . . .\n. . . .\n. . . .\na=3\n", out.String())
+
+	_, err = Parse("cage 3 r1c1 r5c1\n0 0 0 0\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+	_, err = Parse("cage x r1c1\n0 0 0 0\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+}
+
+func TestCageLabel(t *testing.T) {
+	assert.Equal(t, "a", cageLabel(0))
+	assert.Equal(t, "z", cageLabel(25))
+	assert.Equal(t, "aa", cageLabel(26))
+	assert.Equal(t, "ba", cageLabel(52))
+}
@@ -0,0 +1,125 @@
+package sudoku
+
+import "fmt"
+
+type LineKind int
+
+const (
+	// Thermometer values strictly increase from the bulb, Cells[0].
+	Thermometer LineKind = iota
+	// Arrow circle, Cells[0], holds the sum of the rest of the cells.
+	Arrow
+	// Sandwich cells, a whole row or column, add up to Sum between the 1 and
+	// the Size2.
+	Sandwich
+)
+
+func (k LineKind) String() string {
+	switch k {
+	case Thermometer:
+		return "thermo"
+	case Arrow:
+		return "arrow"
+	case Sandwich:
+		return "sandwich"
+	}
+	return "unknown"
+}
+
+// Line is a constraint over a path of cells, see LineKind.
+type Line struct {
+	Kind  LineKind
+	Cells []int // idx
+	Sum   int   // sandwich clue
+}
+
+// AddThermometer adds a thermometer starting from its bulb, cells[0]. Each
+// step is a pair constraint, so it's also enforced by SetValue.
+func (b *Board) AddThermometer(cells []int) error {
+	if len(cells) < 2 {
+		return fmt.Errorf("thermometer needs at least 2 cells")
+	}
+	if len(cells) > b.Size2 {
+		return fmt.Errorf("thermometer longer than %d cells", b.Size2)
+	}
+	if err := b.checkPath(cells); err != nil {
+		return err
+	}
+	for i := 1; i < len(cells); i++ {
+		if err := b.AddPairConstraint(cells[i-1], cells[i], increasing); err != nil {
+			return err
+		}
+	}
+	b.Lines = append(b.Lines, Line{Kind: Thermometer, Cells: append([]int(nil), cells...)})
+
+	// the i-th cell leaves room for i smaller and len-1-i bigger values
+	for i, idx := range cells {
+		for v := 1; v <= b.Size2; v++ {
+			if v <= i || v > b.Size2-(len(cells)-1-i) {
+				b.SetValueFalse(idx/b.Size2, idx%b.Size2, v)
+			}
+		}
+	}
+	return nil
+}
+
+// AddArrow adds an arrow from the circle cell along cells, whose values add
+// up to the circle's.
+func (b *Board) AddArrow(circle int, cells []int) error {
+	if len(cells) == 0 {
+		return fmt.Errorf("arrow needs at least 1 cell besides its circle")
+	}
+	path := append([]int{circle}, cells...)
+	if err := b.checkPath(path); err != nil {
+		return err
+	}
+	b.Lines = append(b.Lines, Line{Kind: Arrow, Cells: path})
+
+	for v := 1; v < len(cells) && v <= b.Size2; v++ {
+		b.SetValueFalse(circle/b.Size2, circle%b.Size2, v)
+	}
+	for _, idx := range cells {
+		for v := b.Size2 - len(cells) + 2; v <= b.Size2; v++ {
+			b.SetValueFalse(idx/b.Size2, idx%b.Size2, v)
+		}
+	}
+	return nil
+}
+
+// AddSandwich adds a sandwich clue to row i, or column i if column is set.
+func (b *Board) AddSandwich(column bool, i, sum int) error {
+	if i < 0 || i >= b.Size2 {
+		return fmt.Errorf("line %d out of range", i+1)
+	}
+	if max := b.Size2*(b.Size2-1)/2 - 1; sum < 0 || sum > max {
+		return fmt.Errorf("sandwich sum %d out of range 0-%d", sum, max)
+	}
+	cells := make([]int, b.Size2)
+	for j := range cells {
+		if column {
+			cells[j] = b.Idx(j, i)
+		} else {
+			cells[j] = b.Idx(i, j)
+		}
+	}
+	b.Lines = append(b.Lines, Line{Kind: Sandwich, Cells: cells, Sum: sum})
+	return nil
+}
+
+func increasing(a, b int) bool {
+	return a < b
+}
+
+func (b *Board) checkPath(cells []int) error {
+	seen := map[int]bool{}
+	for _, idx := range cells {
+		if idx < 0 || idx >= len(b.Lookup) {
+			return fmt.Errorf("cell %d out of range", idx)
+		}
+		if seen[idx] {
+			return fmt.Errorf("%s appears twice", b.CellName(idx))
+		}
+		seen[idx] = true
+	}
+	return nil
+}
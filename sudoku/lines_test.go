@@ -0,0 +1,57 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddThermometer(t *testing.T) {
+	s := New(3)
+	assert.Error(t, s.AddThermometer([]int{0}))
+	assert.Error(t, s.AddThermometer([]int{0, 1, 0}))
+
+	assert.NoError(t, s.AddThermometer([]int{s.Idx(0, 0), s.Idx(0, 1), s.Idx(0, 2)}))
+	assert.Len(t, s.Lines, 1)
+	assert.Len(t, s.PairConstraints, 2)
+	assert.False(t, s.Candidates[s.Lit(0, 0, 8)])
+	assert.True(t, s.Candidates[s.Lit(0, 0, 7)])
+	assert.False(t, s.Candidates[s.Lit(0, 1, 1)])
+	assert.False(t, s.Candidates[s.Lit(0, 1, 9)])
+	assert.False(t, s.Candidates[s.Lit(0, 2, 2)])
+
+	s.SetValue(0, 1, 5)
+	assert.False(t, s.Candidates[s.Lit(0, 0, 6)])
+	assert.True(t, s.Candidates[s.Lit(0, 0, 4)])
+	assert.False(t, s.Candidates[s.Lit(0, 2, 4)])
+}
+
+func TestAddArrow(t *testing.T) {
+	s := New(3)
+	assert.Error(t, s.AddArrow(0, nil))
+	assert.NoError(t, s.AddArrow(s.Idx(0, 0), []int{s.Idx(1, 0), s.Idx(2, 0), s.Idx(3, 0)}))
+	assert.Equal(t, Line{Kind: Arrow, Cells: []int{0, 9, 18, 27}}, s.Lines[0])
+	assert.False(t, s.Candidates[s.Lit(0, 0, 2)])
+	assert.True(t, s.Candidates[s.Lit(0, 0, 3)])
+	assert.True(t, s.Candidates[s.Lit(1, 0, 7)])
+	assert.False(t, s.Candidates[s.Lit(1, 0, 8)])
+}
+
+func TestAddSandwich(t *testing.T) {
+	s := New(3)
+	assert.Error(t, s.AddSandwich(false, 9, 0))
+	assert.Error(t, s.AddSandwich(false, 0, 36))
+	assert.NoError(t, s.AddSandwich(true, 2, 35))
+	assert.Equal(t, Line{Kind: Sandwich, Cells: []int{2, 11, 20, 29, 38, 47, 56, 65, 74}, Sum: 35}, s.Lines[0])
+}
+
+func TestParseLines(t *testing.T) {
+	input := "thermo r1c1 r1c2\narrow r2c1 r3c1\nsandwich c4 2\n" + strings.Repeat("0 0 0 0\n", 4)
+	s, err := Parse(input)
+	assert.NoError(t, err)
+	assert.Equal(t, []LineKind{Thermometer, Arrow, Sandwich}, []LineKind{s.Lines[0].Kind, s.Lines[1].Kind, s.Lines[2].Kind})
+
+	_, err = Parse("sandwich x1 7\n" + strings.Repeat("0 0 0 0\n", 4))
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package sudoku
+
+import "fmt"
+
+// Marker is a clue between two orthogonally adjacent cells.
+type Marker int
+
+const (
+	WhiteDot Marker = iota // consecutive values
+	BlackDot               // one value is double the other
+	XMarker                // values add up to 10
+	VMarker                // values add up to 5
+)
+
+var markerNames = []string{"white", "black", "x", "v"}
+
+func (m Marker) String() string {
+	if m < 0 || int(m) >= len(markerNames) {
+		return "unknown"
+	}
+	return markerNames[m]
+}
+
+// ParseMarker parses a marker name: white, black, x or v.
+func ParseMarker(name string) (Marker, error) {
+	for i, markerName := range markerNames {
+		if name == markerName {
+			return Marker(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown marker %q", name)
+}
+
+// Relation returns the values allowed by the marker.
+func (m Marker) Relation() Relation {
+	switch m {
+	case WhiteDot:
+		return func(a, b int) bool { return a-b == 1 || b-a == 1 }
+	case BlackDot:
+		return func(a, b int) bool { return a == 2*b || b == 2*a }
+	case XMarker:
+		return func(a, b int) bool { return a+b == 10 }
+	case VMarker:
+		return func(a, b int) bool { return a+b == 5 }
+	}
+	return func(a, b int) bool { return false }
+}
+
+// MarkerConstraint is a marker between cells A and B (idx).
+type MarkerConstraint struct {
+	Kind Marker
+	A, B int
+}
+
+// AddMarker puts a marker between two orthogonally adjacent cells.
+func (b *Board) AddMarker(kind Marker, a, c int) error {
+	if a < 0 || a >= len(b.Lookup) || c < 0 || c >= len(b.Lookup) {
+		return fmt.Errorf("cell out of range")
+	}
+	if !b.adjacent(a, c) {
+		return fmt.Errorf("%s and %s aren't adjacent", b.CellName(a), b.CellName(c))
+	}
+	for _, negative := range b.NegativeMarkers {
+		if negative == kind {
+			return fmt.Errorf("%s markers have to be added before the negative constraint", kind)
+		}
+	}
+	relation := kind.Relation()
+	if !b.anyAllowed(relation) {
+		return fmt.Errorf("no values up to %d fit a %s marker", b.Size2, kind)
+	}
+	if err := b.AddPairConstraint(a, c, relation); err != nil {
+		return err
+	}
+	b.Markers = append(b.Markers, MarkerConstraint{Kind: kind, A: a, B: c})
+	return nil
+}
+
+// AddNegativeConstraint declares that all the markers of the given kinds are
+// shown: adjacent cells without any of them can't satisfy any of their
+// relations, e.g. no dot means neither consecutive nor in a 1:2 ratio.
+func (b *Board) AddNegativeConstraint(kinds ...Marker) error {
+	if len(kinds) == 0 {
+		return fmt.Errorf("no marker kinds")
+	}
+	relations := make([]Relation, len(kinds))
+	for i, kind := range kinds {
+		relations[i] = kind.Relation()
+	}
+	none := func(x, y int) bool {
+		for _, relation := range relations {
+			if relation(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+
+	marked := map[[2]int]bool{}
+	for _, m := range b.Markers {
+		for _, kind := range kinds {
+			if m.Kind == kind {
+				marked[[2]int{m.A, m.B}] = true
+				marked[[2]int{m.B, m.A}] = true
+			}
+		}
+	}
+
+	for r := 0; r < b.Size2; r++ {
+		for c := 0; c < b.Size2; c++ {
+			for _, next := range [][2]int{{r, c + 1}, {r + 1, c}} {
+				if next[0] >= b.Size2 || next[1] >= b.Size2 {
+					continue
+				}
+				a, d := b.Idx(r, c), b.Idx(next[0], next[1])
+				if marked[[2]int{a, d}] {
+					continue
+				}
+				if err := b.AddPairConstraint(a, d, none); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	b.NegativeMarkers = append(b.NegativeMarkers, kinds...)
+	return nil
+}
+
+func (b *Board) adjacent(a, c int) bool {
+	dr, dc := a/b.Size2-c/b.Size2, a%b.Size2-c%b.Size2
+	return dr*dr+dc*dc == 1
+}
+
+func (b *Board) anyAllowed(relation Relation) bool {
+	for x := 1; x <= b.Size2; x++ {
+		for y := 1; y <= b.Size2; y++ {
+			if relation(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
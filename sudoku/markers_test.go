@@ -0,0 +1,52 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddMarker(t *testing.T) {
+	s := New(3)
+	assert.Error(t, s.AddMarker(WhiteDot, s.Idx(0, 0), s.Idx(1, 1)))
+	assert.NoError(t, s.AddMarker(BlackDot, s.Idx(0, 0), s.Idx(0, 1)))
+	assert.NoError(t, s.AddMarker(XMarker, s.Idx(1, 0), s.Idx(2, 0)))
+	assert.Len(t, s.Markers, 2)
+
+	s.SetValue(0, 0, 3)
+	assert.True(t, s.Candidates[s.Lit(0, 1, 6)])
+	assert.False(t, s.Candidates[s.Lit(0, 1, 2)])
+	assert.False(t, s.Candidates[s.Lit(0, 1, 4)])
+	s.SetValue(1, 0, 9)
+	assert.True(t, s.Candidates[s.Lit(2, 0, 1)])
+	assert.False(t, s.Candidates[s.Lit(2, 0, 2)])
+
+	small := New(2)
+	assert.Error(t, small.AddMarker(XMarker, 0, 1))
+	assert.NoError(t, small.AddMarker(VMarker, 0, 1))
+}
+
+func TestAddNegativeConstraint(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.AddMarker(WhiteDot, s.Idx(0, 0), s.Idx(0, 1)))
+	assert.Error(t, s.AddNegativeConstraint())
+	assert.NoError(t, s.AddNegativeConstraint(WhiteDot, BlackDot))
+	assert.Error(t, s.AddMarker(BlackDot, s.Idx(1, 0), s.Idx(1, 1)))
+	assert.Len(t, s.PairConstraints, 144)
+
+	s.SetValue(0, 0, 4)
+	assert.True(t, s.Candidates[s.Lit(0, 1, 5)])
+	assert.False(t, s.Candidates[s.Lit(1, 0, 5)])
+	assert.False(t, s.Candidates[s.Lit(1, 0, 2)])
+	assert.False(t, s.Candidates[s.Lit(1, 0, 8)])
+	assert.True(t, s.Candidates[s.Lit(1, 0, 7)])
+}
+
+func TestParseMarker(t *testing.T) {
+	m, err := ParseMarker("v")
+	assert.NoError(t, err)
+	assert.Equal(t, VMarker, m)
+	assert.Equal(t, "black", BlackDot.String())
+	_, err = ParseMarker("grey")
+	assert.Error(t, err)
+}
@@ -0,0 +1,315 @@
+package sudoku
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Multi is a puzzle made of grids overlapping on shared cells, e.g. samurai
+// sudoku. Cells are addressed by their 0-indexed row and column in the
+// Rows x Cols layout, where cells outside every grid are holes.
+type Multi struct {
+	Rows, Cols int
+	Grids      []*Board
+	Corners    [][2]int // top-left (row, col) of each grid in the layout
+
+	cellMap [][]gridCell // layout idx -> the grids covering it
+}
+
+type gridCell struct {
+	grid, idx int
+}
+
+func NewMulti(rows, cols int) *Multi {
+	return &Multi{
+		Rows:    rows,
+		Cols:    cols,
+		cellMap: make([][]gridCell, rows*cols),
+	}
+}
+
+// NewSamurai creates the 21x21 layout of five 9x9 grids, one at each corner
+// and one in the middle sharing a box with each of them.
+func NewSamurai() *Multi {
+	m := NewMulti(21, 21)
+	for _, corner := range [][2]int{{0, 0}, {0, 12}, {6, 6}, {12, 0}, {12, 12}} {
+		m.AddGrid(corner[0], corner[1], New(3))
+	}
+	return m
+}
+
+// AddGrid places grid with its top-left cell at row, col of the layout. The
+// grid has to be empty, and shared cells can't be given different values
+// before solving.
+func (m *Multi) AddGrid(row, col int, grid *Board) error {
+	if row < 0 || col < 0 || row+grid.Size2 > m.Rows || col+grid.Size2 > m.Cols {
+		return fmt.Errorf("%dx%d grid at r%dc%d doesn't fit the %dx%d layout",
+			grid.Size2, grid.Size2, row+1, col+1, m.Rows, m.Cols)
+	}
+	for i, other := range m.Grids {
+		if other.Size2 != grid.Size2 && overlaps(m.Corners[i], other.Size2, [2]int{row, col}, grid.Size2) {
+			return fmt.Errorf("overlapping grids must have the same size")
+		}
+	}
+
+	n := len(m.Grids)
+	m.Grids = append(m.Grids, grid)
+	m.Corners = append(m.Corners, [2]int{row, col})
+	for idx := range grid.Lookup {
+		cell := m.Idx(row+idx/grid.Size2, col+idx%grid.Size2)
+		m.cellMap[cell] = append(m.cellMap[cell], gridCell{n, idx})
+	}
+	return nil
+}
+
+func overlaps(a [2]int, aSize int, b [2]int, bSize int) bool {
+	return a[0] < b[0]+bSize && b[0] < a[0]+aSize && a[1] < b[1]+bSize && b[1] < a[1]+aSize
+}
+
+// 0-indexed
+func (m *Multi) Idx(row, col int) int {
+	return row*m.Cols + col
+}
+
+// Covered reports whether the layout cell is in any grid.
+func (m *Multi) Covered(row, col int) bool {
+	return len(m.cellMap[m.Idx(row, col)]) > 0
+}
+
+// GridCell returns the idx of the layout cell in the i-th grid, or -1 if the
+// grid doesn't cover it.
+func (m *Multi) GridCell(i, row, col int) int {
+	for _, cell := range m.cellMap[m.Idx(row, col)] {
+		if cell.grid == i {
+			return cell.idx
+		}
+	}
+	return -1
+}
+
+// Value returns the value of the layout cell, 0 if it's unknown or a hole.
+func (m *Multi) Value(row, col int) int {
+	cells := m.cellMap[m.Idx(row, col)]
+	if len(cells) == 0 {
+		return 0
+	}
+	return m.Grids[cells[0].grid].Lookup[cells[0].idx]
+}
+
+// SetValue sets the layout cell in every grid covering it.
+func (m *Multi) SetValue(row, col, val int) error {
+	cells := m.cellMap[m.Idx(row, col)]
+	if len(cells) == 0 {
+		return fmt.Errorf("r%dc%d isn't in any grid", row+1, col+1)
+	}
+	for _, cell := range cells {
+		grid := m.Grids[cell.grid]
+		if val < 1 || val > grid.Size2 {
+			return fmt.Errorf("r%dc%d: value %d out of range", row+1, col+1, val)
+		}
+		grid.SetValue(cell.idx/grid.Size2, cell.idx%grid.Size2, val)
+	}
+	return nil
+}
+
+// SyncCandidates makes the shared cells agree across their grids: a candidate
+// removed or a value set in one grid is removed or set in the others. It
+// returns whether anything changed.
+func (m *Multi) SyncCandidates() bool {
+	changed := false
+	for _, cells := range m.cellMap {
+		if len(cells) < 2 {
+			continue
+		}
+		size2 := m.Grids[cells[0].grid].Size2
+		for _, cell := range cells {
+			grid := m.Grids[cell.grid]
+			if val := grid.Lookup[cell.idx]; val != 0 {
+				for _, other := range cells {
+					otherGrid := m.Grids[other.grid]
+					if otherGrid.Lookup[other.idx] == 0 {
+						otherGrid.SetValue(other.idx/size2, other.idx%size2, val)
+						changed = true
+					}
+				}
+			}
+			for v := 1; v <= size2; v++ {
+				if grid.Candidates[grid.Lit(cell.idx/size2, cell.idx%size2, v)] {
+					continue
+				}
+				for _, other := range cells {
+					otherGrid := m.Grids[other.grid]
+					if otherGrid.Candidates[otherGrid.Lit(other.idx/size2, other.idx%size2, v)] {
+						otherGrid.SetValueFalse(other.idx/size2, other.idx%size2, v)
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// BasicSolve runs BasicSolve on every grid, passing what's found on shared
+// cells between them.
+func (m *Multi) BasicSolve() {
+	restart := true
+	for restart {
+		for _, grid := range m.Grids {
+			grid.BasicSolve()
+		}
+		restart = m.SyncCandidates()
+	}
+}
+
+// IsMulti reports whether input is a multi-grid puzzle for ParseMulti.
+func IsMulti(input string) bool {
+	for _, line := range strings.Split(input, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && (strings.EqualFold(fields[0], "samurai") || strings.EqualFold(fields[0], "grid")) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ParseMulti parses a multi-grid puzzle: its grids, then its layout, with "-"
+for holes, "." or 0 for empty cells and digit sets as in Parse. The grids are
+either
+samurai
+or one line per grid with its size and top-left cell, e.g.
+grid 9 r1c1
+grid 9 r7c7
+Comment lines start with #.
+*/
+func ParseMulti(input string) (*Multi, error) {
+	samurai := false
+	var grids [][]string
+	var rows [][]string
+	for _, line := range strings.Split(input, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "samurai":
+			samurai = true
+		case "grid":
+			grids = append(grids, fields)
+		default:
+			rows = append(rows, fields)
+		}
+	}
+
+	var m *Multi
+	if samurai {
+		m = NewSamurai()
+	} else {
+		cols := 0
+		for _, row := range rows {
+			if len(row) > cols {
+				cols = len(row)
+			}
+		}
+		m = NewMulti(len(rows), cols)
+	}
+	for _, fields := range grids {
+		if err := m.parseGrid(fields[1:]); err != nil {
+			return nil, fmt.Errorf("%q: %w", strings.Join(fields, " "), err)
+		}
+	}
+	if len(m.Grids) == 0 {
+		return nil, fmt.Errorf("no grids")
+	}
+
+	if len(rows) != m.Rows {
+		return nil, fmt.Errorf("layout has %d rows, expected %d", len(rows), m.Rows)
+	}
+	for r, row := range rows {
+		for c, token := range row {
+			if token == "-" || token == "." || token == "0" {
+				continue
+			}
+			if c >= m.Cols {
+				return nil, fmt.Errorf("row %d has more than %d cells", r+1, m.Cols)
+			}
+			val, err := strconv.Atoi(token)
+			if err != nil {
+				err = m.restrictCell(r, c, token)
+			} else {
+				err = m.SetValue(r, c, val)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// restrictCell restricts the layout cell to a digit set in every grid
+// covering it, see Board.ParseDigitSet.
+func (m *Multi) restrictCell(row, col int, token string) error {
+	cells := m.cellMap[m.Idx(row, col)]
+	if len(cells) == 0 {
+		return fmt.Errorf("r%dc%d isn't in any grid", row+1, col+1)
+	}
+	for _, cell := range cells {
+		grid := m.Grids[cell.grid]
+		set, err := grid.ParseDigitSet(token)
+		if err != nil {
+			return fmt.Errorf("r%dc%d: %w", row+1, col+1, err)
+		}
+		if err := grid.RestrictCell(cell.idx, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// <size> <cell>
+func (m *Multi) parseGrid(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected a size and a top-left cell")
+	}
+	size2, err := strconv.Atoi(args[0])
+	if err != nil || size2 < 1 {
+		return fmt.Errorf("invalid size %q", args[0])
+	}
+	match := CELL_REGEX.FindStringSubmatch(args[1])
+	if match == nil {
+		return fmt.Errorf("invalid cell %q", args[1])
+	}
+	row, _ := strconv.Atoi(match[1])
+	col, _ := strconv.Atoi(match[2])
+	return m.AddGrid(row-1, col-1, NewRect(BoxShape(size2)))
+}
+
+// Print prints the layout, with "-" for holes.
+func (m *Multi) Print(w io.Writer) {
+	size2 := 0
+	for _, grid := range m.Grids {
+		if grid.Size2 > size2 {
+			size2 = grid.Size2
+		}
+	}
+	width := int(math.Floor(math.Log10(float64(size2)))) + 1
+
+	for r := 0; r < m.Rows; r++ {
+		for c := 0; c < m.Cols; c++ {
+			token := "-"
+			if m.Covered(r, c) {
+				token = strconv.Itoa(m.Value(r, c))
+			}
+			sep := " "
+			if c == m.Cols-1 {
+				sep = "\n"
+			}
+			fmt.Fprintf(w, "%*s%s", width, token, sep)
+		}
+	}
+}
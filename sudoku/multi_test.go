@@ -0,0 +1,64 @@
+package sudoku
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSamurai(t *testing.T) {
+	m := NewSamurai()
+	assert.Len(t, m.Grids, 5)
+	assert.True(t, m.Covered(0, 0))
+	assert.False(t, m.Covered(0, 9))
+	assert.False(t, m.Covered(9, 0))
+	assert.True(t, m.Covered(10, 10))
+
+	assert.NoError(t, m.SetValue(6, 6, 4))
+	assert.Equal(t, 4, m.Value(6, 6))
+	assert.Equal(t, 4, m.Grids[0].Lookup[m.GridCell(0, 6, 6)])
+	assert.Equal(t, 4, m.Grids[2].Lookup[m.GridCell(2, 6, 6)])
+	assert.Equal(t, -1, m.GridCell(1, 6, 6))
+	assert.False(t, m.Grids[2].Candidates[m.Grids[2].Lit(0, 5, 4)])
+	assert.Error(t, m.SetValue(0, 9, 1))
+}
+
+func TestMultiSyncCandidates(t *testing.T) {
+	m := NewSamurai()
+	m.Grids[0].SetValueFalse(8, 8, 3)
+	assert.True(t, m.SyncCandidates())
+	assert.False(t, m.Grids[2].Candidates[m.Grids[2].Lit(2, 2, 3)])
+	assert.False(t, m.SyncCandidates())
+}
+
+func TestParseMulti(t *testing.T) {
+	input := `# two 4x4 grids sharing a box
+grid 4 r1c1
+grid 4 r3c3
+1 0 0 0 - -
+0 0 0 0 - -
+0 0 2 0 0 0
+0 0 0 0 0 0
+- - 0 0 0 3
+- - 0 0 0 0
+`
+	m, err := ParseMulti(input)
+	assert.NoError(t, err)
+	assert.True(t, IsMulti(input))
+	assert.False(t, IsMulti("1 0 0 0\n0 0 0 0"))
+	assert.Equal(t, 6, m.Rows)
+	assert.Equal(t, 6, m.Cols)
+	assert.Equal(t, 2, m.Grids[1].Lookup[0])
+
+	var buf bytes.Buffer
+	m.Print(&buf)
+	assert.Equal(t, "1 0 0 0 - -\n0 0 0 0 - -\n0 0 2 0 0 0\n0 0 0 0 0 0\n- - 0 0 0 3\n- - 0 0 0 0\n", buf.String())
+
+	_, err = ParseMulti("grid 4 r3c3\n1 0 0 0\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+	_, err = ParseMulti("grid 4 r1c1\n1 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+	_, err = ParseMulti("grid 4 r1c1\n1 0 0 0 2\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+package sudoku
+
+import "fmt"
+
+// Relation reports whether two related cells may hold a and b.
+type Relation func(a, b int) bool
+
+// Different forbids equal values, e.g. a knight's move apart in anti-knight
+// sudoku.
+func Different(a, b int) bool {
+	return a != b
+}
+
+// NonConsecutive forbids values differing by one.
+func NonConsecutive(a, b int) bool {
+	return a-b != 1 && b-a != 1
+}
+
+// PairConstraint restricts the values of cells A and B (idx) to the ones
+// allowed by Relation, called with A's value first.
+type PairConstraint struct {
+	A, B     int
+	Relation Relation
+}
+
+// AddPairConstraint relates two cells, eliminating the candidates ruled out
+// by values already set.
+func (b *Board) AddPairConstraint(a, c int, relation Relation) error {
+	if a < 0 || a >= len(b.Lookup) || c < 0 || c >= len(b.Lookup) {
+		return fmt.Errorf("cell out of range")
+	}
+	if a == c {
+		return fmt.Errorf("%s can't be related to itself", b.CellName(a))
+	}
+
+	if b.pairIdxMap == nil {
+		b.pairIdxMap = make([][]int, len(b.Lookup))
+	}
+	pair := len(b.PairConstraints)
+	b.PairConstraints = append(b.PairConstraints, PairConstraint{A: a, B: c, Relation: relation})
+	b.pairIdxMap[a] = append(b.pairIdxMap[a], pair)
+	b.pairIdxMap[c] = append(b.pairIdxMap[c], pair)
+
+	if b.Lookup[a] != 0 {
+		b.eliminatePair(pair, a, b.Lookup[a])
+	}
+	if b.Lookup[c] != 0 {
+		b.eliminatePair(pair, c, b.Lookup[c])
+	}
+	return nil
+}
+
+// eliminatePair removes the candidates of the other cell of a pair that don't
+// go with idx holding val.
+func (b *Board) eliminatePair(pair, idx, val int) {
+	p := b.PairConstraints[pair]
+	other := p.B
+	if idx == p.B {
+		other = p.A
+	}
+	for v := 1; v <= b.Size2; v++ {
+		allowed := p.Relation(val, v)
+		if idx == p.B {
+			allowed = p.Relation(v, val)
+		}
+		if !allowed {
+			b.SetValueFalse(other/b.Size2, other%b.Size2, v)
+		}
+	}
+}
+
+// AddAntiKnight forbids equal values a chess knight's move apart.
+func (b *Board) AddAntiKnight() error {
+	return b.addMoveConstraints([][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}})
+}
+
+// AddAntiKing forbids equal values a chess king's move apart. Orthogonal
+// neighbours already share a row or a column, so only diagonals are added.
+func (b *Board) AddAntiKing() error {
+	return b.addMoveConstraints([][2]int{{1, 1}, {1, -1}})
+}
+
+// AddNonConsecutive forbids consecutive values in orthogonally adjacent cells.
+func (b *Board) AddNonConsecutive() error {
+	for r := 0; r < b.Size2; r++ {
+		for c := 0; c < b.Size2; c++ {
+			if c+1 < b.Size2 {
+				if err := b.AddPairConstraint(b.Idx(r, c), b.Idx(r, c+1), NonConsecutive); err != nil {
+					return err
+				}
+			}
+			if r+1 < b.Size2 {
+				if err := b.AddPairConstraint(b.Idx(r, c), b.Idx(r+1, c), NonConsecutive); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// PairSupport removes the candidates that no candidate of a related cell
+// goes with, returning whether any was removed.
+func (b *Board) PairSupport() bool {
+	changed := false
+	for _, p := range b.PairConstraints {
+		for v := 1; v <= b.Size2; v++ {
+			if b.Candidates[b.Lit(p.A/b.Size2, p.A%b.Size2, v)] && !b.hasSupport(p.B, func(w int) bool { return p.Relation(v, w) }) {
+				b.SetValueFalse(p.A/b.Size2, p.A%b.Size2, v)
+				changed = true
+			}
+			if b.Candidates[b.Lit(p.B/b.Size2, p.B%b.Size2, v)] && !b.hasSupport(p.A, func(w int) bool { return p.Relation(w, v) }) {
+				b.SetValueFalse(p.B/b.Size2, p.B%b.Size2, v)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func (b *Board) hasSupport(idx int, allowed func(w int) bool) bool {
+	for w := 1; w <= b.Size2; w++ {
+		if b.Candidates[b.Lit(idx/b.Size2, idx%b.Size2, w)] && allowed(w) {
+			return true
+		}
+	}
+	return false
+}
+
+// moves only go downwards, so each pair is added once
+func (b *Board) addMoveConstraints(moves [][2]int) error {
+	for r := 0; r < b.Size2; r++ {
+		for c := 0; c < b.Size2; c++ {
+			for _, move := range moves {
+				r2, c2 := r+move[0], c+move[1]
+				if r2 < 0 || r2 >= b.Size2 || c2 < 0 || c2 >= b.Size2 {
+					continue
+				}
+				if err := b.AddPairConstraint(b.Idx(r, c), b.Idx(r2, c2), Different); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
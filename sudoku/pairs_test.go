@@ -0,0 +1,74 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAntiKnight(t *testing.T) {
+	s := New(3)
+	s.SetValue(4, 4, 5)
+	assert.NoError(t, s.AddAntiKnight())
+	assert.Len(t, s.PairConstraints, 224)
+
+	assert.False(t, s.Candidates[s.Lit(2, 3, 5)])
+	assert.False(t, s.Candidates[s.Lit(6, 5, 5)])
+	assert.True(t, s.Candidates[s.Lit(1, 3, 5)])
+
+	s.SetValue(0, 0, 1)
+	assert.False(t, s.Candidates[s.Lit(1, 2, 1)])
+	assert.False(t, s.Candidates[s.Lit(2, 1, 1)])
+	assert.True(t, s.Candidates[s.Lit(1, 2, 2)])
+}
+
+func TestAddAntiKing(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.AddAntiKing())
+	assert.Len(t, s.PairConstraints, 128)
+
+	s.SetValue(3, 3, 7)
+	assert.False(t, s.Candidates[s.Lit(2, 2, 7)])
+	assert.False(t, s.Candidates[s.Lit(4, 2, 7)])
+	assert.True(t, s.Candidates[s.Lit(5, 2, 7)])
+}
+
+func TestAddPairConstraint(t *testing.T) {
+	s := New(2)
+	assert.Error(t, s.AddPairConstraint(0, 0, Different))
+	assert.Error(t, s.AddPairConstraint(0, 16, Different))
+
+	less := func(a, b int) bool { return a < b }
+	assert.NoError(t, s.AddPairConstraint(s.Idx(0, 0), s.Idx(3, 3), less))
+	s.SetValue(3, 3, 3)
+	assert.True(t, s.Candidates[s.Lit(0, 0, 2)])
+	assert.False(t, s.Candidates[s.Lit(0, 0, 3)])
+	assert.False(t, s.Candidates[s.Lit(0, 0, 4)])
+}
+
+func TestAddNonConsecutive(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.AddNonConsecutive())
+	assert.Len(t, s.PairConstraints, 144)
+
+	s.SetValue(4, 4, 5)
+	assert.False(t, s.Candidates[s.Lit(3, 4, 4)])
+	assert.False(t, s.Candidates[s.Lit(4, 5, 6)])
+	assert.True(t, s.Candidates[s.Lit(4, 5, 7)])
+	assert.True(t, s.Candidates[s.Lit(3, 3, 4)])
+}
+
+func TestPairSupport(t *testing.T) {
+	s := New(2)
+	assert.NoError(t, s.AddPairConstraint(s.Idx(0, 0), s.Idx(3, 3), NonConsecutive))
+	for v := 1; v <= 4; v++ {
+		if v != 2 {
+			s.SetValueFalse(3, 3, v)
+		}
+	}
+	assert.True(t, s.PairSupport())
+	assert.False(t, s.Candidates[s.Lit(0, 0, 1)])
+	assert.False(t, s.Candidates[s.Lit(0, 0, 3)])
+	assert.True(t, s.Candidates[s.Lit(0, 0, 4)])
+	assert.False(t, s.PairSupport())
+}
@@ -0,0 +1,96 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Path records how a board was solved, step by step.
+type Path struct {
+	Steps []Step `json:"steps"`
+}
+
+// Record applies the step to b and adds it to the path.
+func (p *Path) Record(b *Board, step Step) {
+	step.Apply(b)
+	p.Steps = append(p.Steps, step)
+}
+
+// RecordSearch places the values of solution still missing from b, found by
+// search rather than by a technique.
+func (p *Path) RecordSearch(b *Board, solution *Board) {
+	step := Step{Technique: Search}
+	for idx, val := range b.Lookup {
+		if val == 0 && solution.Lookup[idx] != 0 {
+			step.Placements = append(step.Placements, b.candidate(idx, solution.Lookup[idx]))
+		}
+	}
+	if len(step.Placements) > 0 {
+		p.Record(b, step)
+	}
+}
+
+// Searched reports whether part of the path was found by search.
+func (p *Path) Searched() bool {
+	for _, step := range p.Steps {
+		if step.Technique == Search {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes the steps, one per line.
+func (p *Path) Print(w io.Writer) {
+	for i, step := range p.Steps {
+		fmt.Fprintf(w, "%d. %s\n", i+1, step)
+	}
+}
+
+// SolvePath solves the board like LogicalSolve, recording every step.
+//
+// Boards with more than maxPathSize2 values are left to search with an empty
+// path, as their paths run to thousands of steps.
+func (b *Board) SolvePath() *Path {
+	return b.solvePath(b.FindStep)
+}
+
+const maxPathSize2 = 36
+
+func (b *Board) solvePath(next func() *Step) *Path {
+	p := &Path{}
+	if b.Size2 > maxPathSize2 {
+		return p
+	}
+	for step := next(); step != nil; step = next() {
+		p.Record(b, *step)
+	}
+	return p
+}
+
+// MarshalJSON writes the step with its cells and candidates in the same
+// notation as String.
+func (s Step) MarshalJSON() ([]byte, error) {
+	step := struct {
+		Technique    string   `json:"technique"`
+		Cells        []string `json:"cells,omitempty"`
+		Values       []int    `json:"values,omitempty"`
+		Chain        []string `json:"chain,omitempty"`
+		Placements   []string `json:"placements,omitempty"`
+		Eliminations []string `json:"eliminations,omitempty"`
+	}{Technique: s.Technique.String(), Values: s.Values}
+	for _, c := range s.Cells {
+		step.Cells = append(step.Cells, c.String())
+	}
+	for _, c := range s.Chain {
+		step.Chain = append(step.Chain, fmt.Sprintf("(%d)%s", c.Value, c.Cell))
+	}
+	for _, p := range s.Placements {
+		step.Placements = append(step.Placements, fmt.Sprintf("%s=%d", p.Cell, p.Value))
+	}
+	for _, e := range s.Eliminations {
+		step.Eliminations = append(step.Eliminations, fmt.Sprintf("%s<>%d", e.Cell, e.Value))
+	}
+	return json.Marshal(step)
+}
@@ -0,0 +1,98 @@
+package sudoku
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolvePath(t *testing.T) {
+	input, _ := ioutil.ReadFile("../data/sudoku-hyper-9-1.txt")
+	s := NewFromString(string(input))
+	path := s.SolvePath()
+	assert.True(t, s.Solved())
+	assert.False(t, path.Searched())
+
+	replayed := NewFromString(string(input))
+	for _, step := range path.Steps {
+		step.Apply(replayed)
+	}
+	assert.Equal(t, s.Lookup, replayed.Lookup)
+
+	var out bytes.Buffer
+	path.Print(&out)
+	assert.True(t, strings.HasPrefix(out.String(), "1. hidden single r3c3 {5}: r3c3=5\n2. hidden single r1c3 {7}: r1c3=7\n"))
+}
+
+func TestSolvePathLargeBoard(t *testing.T) {
+	input, _ := ioutil.ReadFile("../data/sudoku-64-1.txt")
+	s := NewFromString(string(input))
+	assert.NotNil(t, s.FindStep())
+
+	done := make(chan *Path)
+	go func() { done <- s.SolvePath() }()
+	select {
+	case path := <-done:
+		assert.Empty(t, path.Steps)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no path after 5s on a 64x64 board")
+	}
+}
+
+func TestSolvePathPairRelations(t *testing.T) {
+	input, _ := ioutil.ReadFile("../data/sudoku-kropki-9-1.txt")
+	s := NewFromString(string(input))
+	assert.Equal(t, PairRelation, s.FindStep().Technique)
+	path := s.SolvePath()
+	assert.Equal(t, PairRelation, path.Steps[0].Technique)
+	assert.Contains(t, path.Steps[0].Eliminations, Candidate{Cell{0, 0}, 5})
+
+	replayed := NewFromString(string(input))
+	for _, step := range path.Steps {
+		step.Apply(replayed)
+	}
+	assert.Equal(t, s.Lookup, replayed.Lookup)
+	assert.Equal(t, s.Candidates, replayed.Candidates)
+}
+
+func TestRecordSearch(t *testing.T) {
+	s := New(2)
+	solution := New(2)
+	for idx, c := range "1234341221434321" {
+		solution.SetValue(idx/4, idx%4, int(c-'0'))
+	}
+	s.SetValue(0, 0, 1)
+
+	path := &Path{}
+	path.RecordSearch(s, solution)
+	assert.True(t, path.Searched())
+	assert.Len(t, path.Steps[0].Placements, 15)
+	assert.Equal(t, solution.Lookup, s.Lookup)
+
+	path.RecordSearch(s, solution)
+	assert.Len(t, path.Steps, 1)
+}
+
+func TestStepJSON(t *testing.T) {
+	step := Step{
+		Technique:    XYWing,
+		Cells:        []Cell{{0, 0}, {0, 4}, {4, 0}},
+		Values:       []int{1, 2, 3},
+		Chain:        []Candidate{{Cell{0, 4}, 3}, {Cell{0, 4}, 1}},
+		Eliminations: []Candidate{{Cell{4, 4}, 3}},
+	}
+	out, err := json.Marshal(step)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"technique": "xy-wing",
+		"cells": ["r1c1", "r1c5", "r5c1"],
+		"values": [1, 2, 3],
+		"chain": ["(3)r1c5", "(1)r1c5"],
+		"eliminations": ["r5c5<>3"]
+	}`, string(out))
+}
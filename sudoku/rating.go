@@ -0,0 +1,111 @@
+package sudoku
+
+// ratings are Sudoku Explainer ratings of the techniques. The ones it
+// doesn't have are rated like its closest ones, the other constraints like
+// singles and search above any technique.
+var ratings = []float64{
+	NakedSingle:     2.3,
+	HiddenSingle:    1.5,
+	NakedPair:       3.0,
+	NakedTriple:     3.6,
+	NakedQuad:       5.0,
+	HiddenPair:      3.4,
+	HiddenTriple:    4.0,
+	HiddenQuad:      5.4,
+	Pointing:        2.6,
+	Claiming:        2.8,
+	XWing:           3.2,
+	Swordfish:       3.8,
+	Jellyfish:       5.2,
+	XYWing:          4.2,
+	XYZWing:         4.4,
+	WWing:           4.4,
+	SimpleColoring:  4.5,
+	XChain:          6.6,
+	AIC:             7.0,
+	PairRelation:    2.0,
+	InequalityBound: 2.0,
+	Search:          10.0,
+}
+
+// Rating is the Sudoku Explainer style rating of the technique.
+func (t Technique) Rating() float64 {
+	if t < 0 || int(t) >= len(ratings) {
+		return 0
+	}
+	return ratings[t]
+}
+
+func (t Technique) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// Difficulty is a coarse grading of a rating.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+)
+
+var difficultyNames = []string{"easy", "medium", "hard", "expert"}
+
+func (d Difficulty) String() string {
+	if d < 0 || int(d) >= len(difficultyNames) {
+		return "unknown"
+	}
+	return difficultyNames[d]
+}
+
+func (d Difficulty) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// DifficultyOf grades a rating: easy with singles only, medium up to hidden
+// pairs, hard up to the wings and simple coloring, expert beyond.
+func DifficultyOf(rating float64) Difficulty {
+	switch {
+	case rating <= NakedSingle.Rating():
+		return Easy
+	case rating <= HiddenPair.Rating():
+		return Medium
+	case rating <= SimpleColoring.Rating():
+		return Hard
+	}
+	return Expert
+}
+
+// RatedPath is SolvePath taking the steps in the order of their ratings, so
+// the hardest step is no harder than needed. The finders are already in that
+// order but for the naked single, rated above the hidden single, the pair
+// relation and the inequality bound.
+func (b *Board) RatedPath() *Path {
+	return b.solvePath(func() *Step {
+		for _, find := range ratedBelowNakedSingle {
+			if step := find(b); step != nil {
+				return step
+			}
+		}
+		return b.FindStep()
+	})
+}
+
+var ratedBelowNakedSingle = []func(b *Board) *Step{
+	findHiddenSingle,
+	(*Board).findPairRelation,
+	(*Board).findInequalityBound,
+}
+
+// Hardest returns the technique of the highest rated step, and its rating.
+// An empty path rates 0.
+func (p *Path) Hardest() (Technique, float64) {
+	hardest, rating := NakedSingle, 0.0
+	for _, step := range p.Steps {
+		if r := step.Technique.Rating(); r > rating {
+			hardest, rating = step.Technique, r
+		}
+	}
+	return hardest, rating
+}
@@ -0,0 +1,61 @@
+package sudoku
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDifficultyOf(t *testing.T) {
+	assert.Equal(t, Easy, DifficultyOf(HiddenSingle.Rating()))
+	assert.Equal(t, Easy, DifficultyOf(NakedSingle.Rating()))
+	assert.Equal(t, Medium, DifficultyOf(Pointing.Rating()))
+	assert.Equal(t, Medium, DifficultyOf(HiddenPair.Rating()))
+	assert.Equal(t, Hard, DifficultyOf(XYWing.Rating()))
+	assert.Equal(t, Expert, DifficultyOf(Jellyfish.Rating()))
+	assert.Equal(t, Expert, DifficultyOf(Search.Rating()))
+	assert.Equal(t, "expert", Expert.String())
+}
+
+func TestHardest(t *testing.T) {
+	path := &Path{}
+	_, rating := path.Hardest()
+	assert.Equal(t, 0.0, rating)
+
+	path.Steps = []Step{{Technique: NakedSingle}, {Technique: XWing}, {Technique: Pointing}}
+	technique, rating := path.Hardest()
+	assert.Equal(t, XWing, technique)
+	assert.Equal(t, 3.2, rating)
+
+	for technique := NakedSingle; technique <= Search; technique++ {
+		assert.NotZero(t, technique.Rating(), technique.String())
+	}
+}
+
+func TestRatedPath(t *testing.T) {
+	// hidden singles all the way, though a naked single is found first
+	const puzzle = "000000010400000000020000000000050407008000300001090000300400200050100000000806000"
+	path := NewFromSingleRowString(puzzle).SolvePath()
+	technique, _ := path.Hardest()
+	assert.Equal(t, NakedSingle, technique)
+
+	board := NewFromSingleRowString(puzzle)
+	path = board.RatedPath()
+	technique, rating := path.Hardest()
+	assert.Equal(t, HiddenSingle, technique)
+	assert.Equal(t, 1.5, rating)
+	assert.True(t, board.Solved())
+}
+
+func TestRatedPathConstraints(t *testing.T) {
+	// singles and the kropki dots are enough, no naked pair needed
+	input, _ := ioutil.ReadFile("../data/sudoku-kropki-9-1.txt")
+	board := NewFromString(string(input))
+	path := board.RatedPath()
+	technique, rating := path.Hardest()
+	assert.Equal(t, NakedSingle, technique)
+	assert.Equal(t, 2.3, rating)
+	assert.True(t, board.Solved())
+	assert.Equal(t, PairRelation, path.Steps[0].Technique)
+}
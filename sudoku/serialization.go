@@ -1,15 +1,39 @@
 package sudoku
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"math"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
-var SPACE_REGEX = regexp.MustCompile(`  +`)
+var CELL_REGEX = regexp.MustCompile(`^[rR](\d+)[cC](\d+)$`)
+
+// directives are the header lines accepted before the grid, keyed by their
+// first word. They run on the empty board, before the givens are set.
+var directives = map[string]func(b *Board, args []string) error{
+	"antiking":       parseAntiKing,
+	"antiknight":     parseAntiKnight,
+	"arrow":          parseArrow,
+	"black":          markerDirective(BlackDot),
+	"box":            parseBox,
+	"cage":           parseCage,
+	"diagonals":      parseDiagonals,
+	"greater":        parseGreater,
+	"latin":          parseLatin,
+	"less":           parseLess,
+	"negative":       parseNegative,
+	"nonconsecutive": parseNonConsecutive,
+	"regions":        parseRegions,
+	"sandwich":       parseSandwich,
+	"thermo":         parseThermo,
+	"v":              markerDirective(VMarker),
+	"white":          markerDirective(WhiteDot),
+	"windows":        parseWindows,
+	"x":              markerDirective(XMarker),
+}
 
 /*
 Parse newline and space separated sudoku problem
@@ -17,48 +41,348 @@ Parse newline and space separated sudoku problem
 9 0 0 ...
 0 0 1 ...
 ...
+
+The box shape is inferred from the number of rows, e.g. 2x3 boxes for a 6x6
+grid. The grid can be preceded by directive lines for variants, e.g.
+box 3x2
+cage 15 r1c1 r1c2 r2c1
+diagonals
+windows
+antiknight
+nonconsecutive
+thermo r1c1 r1c2 r1c3
+arrow r2c2 r2c3 r3c3
+sandwich r1 15
+white r1c1 r1c2
+negative white black
+less r1c1 r1c2
+greater r1c1 r2c1
+latin
+regions 111222333 111222333 ...
+and by comment lines starting with #. Cells can also be restricted to a digit
+set instead of a value, e.g. e for even, see ParseDigitSet.
 */
 func NewFromString(input string) *Board {
-	input = strings.Trim(input, " \n\t")
-	input = SPACE_REGEX.ReplaceAllString(input, " ")
-	input = strings.ReplaceAll(input, ".", "0")
+	board, err := Parse(input)
+	if err != nil {
+		panic(err)
+	}
+	return board
+}
+
+// Parse is NewFromString returning an error on invalid input.
+func Parse(input string) (*Board, error) {
+	return ParseWith(input)
+}
 
-	// standard 9x9 single row
-	if strings.Index(input, "\n") == -1 {
-		return NewFromSingleRowString(input)
+// ParseWith is Parse running setup on the board after the directives, before
+// the givens are set, for variants chosen outside the input, e.g.
+// (*Board).RemoveBoxes.
+func ParseWith(input string, setup ...func(b *Board) error) (*Board, error) {
+	var header [][]string
+	var rows []string
+	for _, line := range strings.Split(strings.Trim(input, " \n\t"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if _, ok := directives[strings.ToLower(fields[0])]; ok {
+			header = append(header, fields)
+			continue
+		}
+		rows = append(rows, line)
 	}
 
-	r := bufio.NewReader(strings.NewReader(input))
+	var tokens []string
+	var err error
+	if len(rows) == 1 {
+		// standard 9x9 single row
+		tokens, err = oneLineTokens(rows[0])
+		if err == nil && len(tokens) != 81 {
+			err = fmt.Errorf("one-line sudoku has %d cells, expected 81", len(tokens))
+		}
+	} else {
+		for _, row := range rows {
+			tokens = append(tokens, strings.Fields(row)...)
+		}
+		if len(tokens) != len(rows)*len(rows) {
+			err = fmt.Errorf("%d rows have %d cells, expected %d", len(rows), len(tokens), len(rows)*len(rows))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	rows := strings.Split(input, "\n")
-	size2 := len(rows)
-	cells := make([][]int, size2)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty grid")
+	}
+	board := NewRect(BoxShape(int(math.Sqrt(float64(len(tokens))))))
 
-	for i := 0; i < size2; i++ {
-		cells[i] = make([]int, size2)
-		for j := 0; j < size2; j++ {
-			fmt.Fscan(r, &cells[i][j])
+	for _, fields := range header {
+		if err := directives[strings.ToLower(fields[0])](board, fields[1:]); err != nil {
+			return nil, fmt.Errorf("%q: %w", strings.Join(fields, " "), err)
+		}
+	}
+	for _, f := range setup {
+		if err := f(board); err != nil {
+			return nil, err
 		}
 	}
 
-	return NewFromArray(cells)
+	if err := board.setTokens(tokens); err != nil {
+		return nil, err
+	}
+	return board, nil
 }
 
-func NewFromSingleRowString(input string) *Board {
-	size2 := 9
-	size := 3
-	board := New(size)
+// oneLineTokens splits a one-line sudoku into its cells, one character each
+// except for [...] digit sets.
+func oneLineTokens(input string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(input); i++ {
+		if input[i] != '[' {
+			tokens = append(tokens, input[i:i+1])
+			continue
+		}
+		end := strings.IndexByte(input[i:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed %q", input[i:])
+		}
+		tokens = append(tokens, input[i:i+end+1])
+		i += end
+	}
+	return tokens, nil
+}
+
+// setTokens restricts and sets the cells, in idx order, from their tokens: a
+// value, 0 or . when empty, or a digit set, see ParseDigitSet.
+func (b *Board) setTokens(tokens []string) error {
+	values := make([]int, len(tokens))
+	for idx, token := range tokens {
+		if token == "." {
+			continue
+		}
+		if val, err := strconv.Atoi(token); err == nil {
+			if val < 0 || val > b.Size2 {
+				return fmt.Errorf("%s: value %d out of range", b.CellName(idx), val)
+			}
+			values[idx] = val
+			continue
+		}
+		set, err := b.ParseDigitSet(token)
+		if err != nil {
+			return fmt.Errorf("%s: %w", b.CellName(idx), err)
+		}
+		if err := b.RestrictCell(idx, set); err != nil {
+			return err
+		}
+	}
+
+	for idx, val := range values {
+		if val != 0 {
+			b.SetValue(idx/b.Size2, idx%b.Size2, val)
+		}
+	}
+	return nil
+}
+
+// cage <sum> <cell>...
+func parseCage(b *Board, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("expected a sum and at least one cell")
+	}
+	sum, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid sum %q", args[0])
+	}
+	cells, err := b.parseCells(args[1:])
+	if err != nil {
+		return err
+	}
+	return b.AddCage(cells, sum)
+}
+
+// thermo <bulb> <cell>...
+func parseThermo(b *Board, args []string) error {
+	cells, err := b.parseCells(args)
+	if err != nil {
+		return err
+	}
+	return b.AddThermometer(cells)
+}
+
+// arrow <circle> <cell>...
+func parseArrow(b *Board, args []string) error {
+	cells, err := b.parseCells(args)
+	if err != nil {
+		return err
+	}
+	if len(cells) == 0 {
+		return fmt.Errorf("expected a circle and its arrow")
+	}
+	return b.AddArrow(cells[0], cells[1:])
+}
+
+// sandwich r<row> <sum> or sandwich c<col> <sum>
+func parseSandwich(b *Board, args []string) error {
+	var line rune
+	var i, sum int
+	if len(args) != 2 {
+		return fmt.Errorf("expected a row or column and a sum")
+	}
+	if _, err := fmt.Sscanf(strings.ToLower(args[0]), "%c%d", &line, &i); err != nil || (line != 'r' && line != 'c') {
+		return fmt.Errorf("invalid row or column %q", args[0])
+	}
+	sum, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid sum %q", args[1])
+	}
+	return b.AddSandwich(line == 'c', i-1, sum)
+}
 
-	for i, c := range input {
-		if c != '0' && c != '.' {
-			board.SetValue(i/size2, i%size2, int(c-'0'))
+func (b *Board) parseCells(names []string) ([]int, error) {
+	cells := make([]int, len(names))
+	for i, name := range names {
+		var err error
+		if cells[i], err = b.ParseCell(name); err != nil {
+			return nil, err
 		}
 	}
+	return cells, nil
+}
+
+// white|black|x|v <cell> <cell>
+func markerDirective(kind Marker) func(b *Board, args []string) error {
+	return func(b *Board, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("expected two cells")
+		}
+		cells, err := b.parseCells(args)
+		if err != nil {
+			return err
+		}
+		return b.AddMarker(kind, cells[0], cells[1])
+	}
+}
+
+// negative <marker>..., after the markers
+func parseNegative(b *Board, args []string) error {
+	kinds := make([]Marker, len(args))
+	for i, name := range args {
+		var err error
+		if kinds[i], err = ParseMarker(strings.ToLower(name)); err != nil {
+			return err
+		}
+	}
+	return b.AddNegativeConstraint(kinds...)
+}
+
+// less <cell> <cell>, the first cell being the smaller one
+func parseLess(b *Board, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected two cells")
+	}
+	cells, err := b.parseCells(args)
+	if err != nil {
+		return err
+	}
+	return b.AddInequality(cells[0], cells[1])
+}
+
+// greater <cell> <cell>, the first cell being the greater one
+func parseGreater(b *Board, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected two cells")
+	}
+	return parseLess(b, []string{args[1], args[0]})
+}
+
+// latin, for latin squares and futoshiki without boxes
+func parseLatin(b *Board, args []string) error {
+	return b.RemoveBoxes()
+}
+
+// box <rows>x<cols>, for when the inferred box shape isn't the right one
+func parseBox(b *Board, args []string) error {
+	var boxRows, boxCols int
+	if len(args) != 1 {
+		return fmt.Errorf("expected <rows>x<cols>")
+	}
+	if _, err := fmt.Sscanf(args[0], "%dx%d", &boxRows, &boxCols); err != nil {
+		return fmt.Errorf("invalid box shape %q", args[0])
+	}
+	return b.SetBoxShape(boxRows, boxCols)
+}
+
+// antiknight
+func parseAntiKnight(b *Board, args []string) error {
+	return b.AddAntiKnight()
+}
+
+// antiking
+func parseAntiKing(b *Board, args []string) error {
+	return b.AddAntiKing()
+}
 
+// nonconsecutive
+func parseNonConsecutive(b *Board, args []string) error {
+	return b.AddNonConsecutive()
+}
+
+// diagonals, for Sudoku-X
+func parseDiagonals(b *Board, args []string) error {
+	return b.AddDiagonals()
+}
+
+// windows, for Hyper Sudoku
+func parseWindows(b *Board, args []string) error {
+	return b.AddWindows()
+}
+
+// regions <map>..., one character per cell labelling its region, e.g.
+// regions 111222333 111222333 ...
+func parseRegions(b *Board, args []string) error {
+	labels := []rune(strings.Join(args, ""))
+	regionMap := make([]int, len(labels))
+	ids := map[rune]int{}
+	for i, label := range labels {
+		if _, ok := ids[label]; !ok {
+			ids[label] = len(ids)
+		}
+		regionMap[i] = ids[label]
+	}
+	if len(ids) != b.Size2 {
+		return fmt.Errorf("found %d regions, expected %d", len(ids), b.Size2)
+	}
+	return b.SetRegions(regionMap)
+}
+
+// ParseCell parses a 1-indexed r<row>c<col> cell name into its idx.
+func (b *Board) ParseCell(name string) (int, error) {
+	match := CELL_REGEX.FindStringSubmatch(name)
+	if match == nil {
+		return 0, fmt.Errorf("invalid cell %q", name)
+	}
+	row, _ := strconv.Atoi(match[1])
+	col, _ := strconv.Atoi(match[2])
+	if row < 1 || row > b.Size2 || col < 1 || col > b.Size2 {
+		return 0, fmt.Errorf("cell %q out of range", name)
+	}
+	return b.Idx(row-1, col-1), nil
+}
+
+func NewFromSingleRowString(input string) *Board {
+	board := New(3)
+	if err := board.setOneLine(input); err != nil {
+		panic(err)
+	}
 	return board
 }
 
-func (b *Board) ReplaceWithSingleRowString(input string, skipCandidateElimination bool) {
+// ReplaceWithSingleRowString resets the 9x9 board to the one-line sudoku
+// input.
+func (b *Board) ReplaceWithSingleRowString(input string, skipCandidateElimination bool) error {
 	size2 := 9
 	b.NumCandidates = len(b.Candidates) - 1
 
@@ -74,29 +398,39 @@ func (b *Board) ReplaceWithSingleRowString(input string, skipCandidateEliminatio
 		b.colCandidateCount[i] = size2
 		b.blkCandidateCount[i] = size2
 	}
+	for i := range b.houseCandidateCount {
+		b.houseCandidateCount[i] = size2
+	}
 
-	for i, c := range input {
-		if c != '0' && c != '.' {
-			b.SetValue(i/size2, i%size2, int(c-'0'))
-		}
+	return b.setOneLine(input)
+}
+
+func (b *Board) setOneLine(input string) error {
+	tokens, err := oneLineTokens(input)
+	if err != nil {
+		return err
+	}
+	if len(tokens) != len(b.Lookup) {
+		return fmt.Errorf("one-line sudoku has %d cells, expected %d", len(tokens), len(b.Lookup))
 	}
+	return b.setTokens(tokens)
 }
 
 func NewFromArray(cells [][]int) *Board {
-	size2 := len(cells)
-	size := getSize(size2)
-	board := New(size)
+	board := NewRect(BoxShape(len(cells)))
+	board.setCells(cells)
+	return board
+}
 
+func (b *Board) setCells(cells [][]int) {
 	for r, row := range cells {
 		for c, val := range row {
-			if val < 1 || val > size2 {
+			if val < 1 || val > b.Size2 {
 				continue
 			}
-			board.SetValue(r, c, val)
+			b.SetValue(r, c, val)
 		}
 	}
-
-	return board
 }
 
 func (s *Board) Print(w io.Writer) {
@@ -32,3 +32,66 @@ func TestParseFromSingleRowString(t *testing.T) {
 	assert.Equal(t, 3, board.Size)
 	assert.Equal(t, 3, board.Lookup[board.Idx(8, 8)])
 }
+
+func TestParseRegions(t *testing.T) {
+	input := `
+	regions aabb aabb cdcd cdcd
+	0 0 0 0
+	0 0 0 0
+	0 0 0 0
+	0 0 0 0`
+
+	board, err := Parse(input)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8, 10, 12, 14}, board.Blocks[2])
+
+	_, err = Parse("regions aabb aabb cdcd cdce\n0 0 0 0\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+}
+
+func TestParseRectangular(t *testing.T) {
+	input := `
+	0 0 0 0 0 0
+	0 0 0 0 0 0
+	0 0 0 0 0 0
+	0 0 0 0 0 0
+	0 0 0 0 0 0
+	0 0 0 0 0 6`
+
+	board, err := Parse(input)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, board.BoxRows)
+	assert.Equal(t, 3, board.BoxCols)
+	assert.Equal(t, 6, board.Lookup[board.Idx(5, 5)])
+
+	board, err = Parse("box 3x2\n" + input)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, board.BoxRows)
+	assert.Equal(t, 2, board.BoxCols)
+	assert.False(t, board.Candidates[board.Lit(3, 4, 6)])
+	assert.True(t, board.Candidates[board.Lit(3, 3, 6)])
+
+	_, err = Parse("box 4x2\n" + input)
+	assert.Error(t, err)
+}
+
+func TestParseWith(t *testing.T) {
+	// a 1 in the first box, only the boxes rule it out of r2c2
+	input := "1 0 0 0\n0 0 0 0\n0 0 0 0\n0 0 0 0"
+	board, err := Parse(input)
+	assert.NoError(t, err)
+	assert.False(t, board.Candidates[board.Lit(1, 1, 1)])
+
+	board, err = ParseWith(input, (*Board).RemoveBoxes)
+	assert.NoError(t, err)
+	assert.False(t, board.HasBoxes())
+	assert.True(t, board.Candidates[board.Lit(1, 1, 1)])
+}
+
+func TestParseMalformedGrid(t *testing.T) {
+	_, err := Parse("1 2 4\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+
+	_, err = Parse("1 2 x 4\n0 0 0 0\n0 0 0 0\n0 0 0 0")
+	assert.Error(t, err)
+}
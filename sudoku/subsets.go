@@ -0,0 +1,142 @@
+package sudoku
+
+var nakedSubsets = []Technique{2: NakedPair, 3: NakedTriple, 4: NakedQuad}
+var hiddenSubsets = []Technique{2: HiddenPair, 3: HiddenTriple, 4: HiddenQuad}
+
+// nakedSubsetFinder finds n cells of a house with only n candidates between
+// them, which can then be removed from the rest of the house.
+func nakedSubsetFinder(n int) func(b *Board) *Step {
+	return func(b *Board) *Step {
+		for _, house := range b.allHouses() {
+			var cells []int
+			var candidates [][]int
+			for _, idx := range house {
+				if values := b.candidateValues(idx); len(values) >= 2 && len(values) <= n {
+					cells = append(cells, idx)
+					candidates = append(candidates, values)
+				}
+			}
+
+			var step *Step
+			lockedSets(cells, candidates, n, func(subset, values []int) bool {
+				var eliminations []Candidate
+				for _, idx := range house {
+					if contains(subset, idx) {
+						continue
+					}
+					for _, v := range values {
+						if b.isCandidate(idx, v) {
+							eliminations = append(eliminations, b.candidate(idx, v))
+						}
+					}
+				}
+				if len(eliminations) == 0 {
+					return false
+				}
+				step = &Step{
+					Technique:    nakedSubsets[n],
+					Cells:        b.cells(subset),
+					Values:       values,
+					Eliminations: eliminations,
+				}
+				return true
+			})
+			if step != nil {
+				return step
+			}
+		}
+		return nil
+	}
+}
+
+// hiddenSubsetFinder finds n values that only fit in the same n cells of a
+// house, which can then hold no other value.
+func hiddenSubsetFinder(n int) func(b *Board) *Step {
+	return func(b *Board) *Step {
+		for _, house := range b.allHouses() {
+			var values []int
+			var positions [][]int
+			for v := 1; v <= b.Size2; v++ {
+				if cells := b.positions(house, v); len(cells) >= 2 && len(cells) <= n {
+					values = append(values, v)
+					positions = append(positions, cells)
+				}
+			}
+
+			var step *Step
+			lockedSets(values, positions, n, func(subset, cells []int) bool {
+				var eliminations []Candidate
+				for _, idx := range house {
+					if !contains(cells, idx) {
+						continue
+					}
+					for _, v := range b.candidateValues(idx) {
+						if !contains(subset, v) {
+							eliminations = append(eliminations, b.candidate(idx, v))
+						}
+					}
+				}
+				if len(eliminations) == 0 {
+					return false
+				}
+				step = &Step{
+					Technique:    hiddenSubsets[n],
+					Cells:        b.cells(cells),
+					Values:       append([]int(nil), subset...),
+					Eliminations: eliminations,
+				}
+				return true
+			})
+			if step != nil {
+				return step
+			}
+		}
+		return nil
+	}
+}
+
+// positions lists the cells of the house where v is a candidate, or nothing
+// if v is already placed in it.
+func (b *Board) positions(house []int, v int) []int {
+	var cells []int
+	for _, idx := range house {
+		if b.Lookup[idx] == v {
+			return nil
+		}
+		if b.isCandidate(idx, v) {
+			cells = append(cells, idx)
+		}
+	}
+	return cells
+}
+
+// unionValues lists the candidates of any of the cells, in order.
+func (b *Board) unionValues(cells []int) []int {
+	var values []int
+	for v := 1; v <= b.Size2; v++ {
+		for _, idx := range cells {
+			if b.isCandidate(idx, v) {
+				values = append(values, v)
+				break
+			}
+		}
+	}
+	return values
+}
+
+func (b *Board) cells(idxs []int) []Cell {
+	cells := make([]Cell, len(idxs))
+	for i, idx := range idxs {
+		cells[i] = b.cell(idx)
+	}
+	return cells
+}
+
+func contains(items []int, item int) bool {
+	for _, x := range items {
+		if x == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNakedSubsets(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 0), []int{3, 7}))
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 4), []int{3, 7}))
+	step := s.FindStep()
+	assert.Equal(t, NakedPair, step.Technique)
+	assert.Equal(t, []Cell{{0, 0}, {0, 4}}, step.Cells)
+	assert.Equal(t, []int{3, 7}, step.Values)
+	assert.Len(t, step.Eliminations, 14)
+	assert.Contains(t, step.Eliminations, Candidate{Cell{0, 8}, 7})
+
+	s = New(3)
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 0), []int{1, 2}))
+	assert.NoError(t, s.RestrictCell(s.Idx(4, 0), []int{2, 3}))
+	assert.NoError(t, s.RestrictCell(s.Idx(8, 0), []int{1, 3}))
+	step = s.FindStep()
+	assert.Equal(t, NakedTriple, step.Technique)
+	assert.Equal(t, []Cell{{0, 0}, {4, 0}, {8, 0}}, step.Cells)
+	assert.Equal(t, []int{1, 2, 3}, step.Values)
+	assert.Len(t, step.Eliminations, 18)
+}
+
+func TestHiddenSubsets(t *testing.T) {
+	s := New(3)
+	for c := 2; c < 9; c++ {
+		s.SetValueFalse(0, c, 1)
+		s.SetValueFalse(0, c, 2)
+	}
+	step := hiddenSubsetFinder(2)(s)
+	assert.Equal(t, HiddenPair, step.Technique)
+	assert.Equal(t, []Cell{{0, 0}, {0, 1}}, step.Cells)
+	assert.Equal(t, []int{1, 2}, step.Values)
+	assert.Len(t, step.Eliminations, 14)
+	assert.Equal(t, "hidden pair r1c1 r1c2 {1 2}: r1c1<>3", step.String()[:36])
+
+	step.Apply(s)
+	assert.Equal(t, []int{1, 2}, s.candidateValues(s.Idx(0, 0)))
+}
+
+func TestLockedSets(t *testing.T) {
+	var found [][]int
+	sets := [][]int{{1, 2}, {2, 3}, {4, 5}, {1, 3}, {1, 2, 3}}
+	lockedSets([]int{10, 11, 12, 13, 14}, sets, 3, func(subset, union []int) bool {
+		assert.Equal(t, []int{1, 2, 3}, union)
+		found = append(found, append([]int(nil), subset...))
+		return len(found) == 3
+	})
+	assert.Equal(t, [][]int{{10, 11, 13}, {10, 11, 14}, {10, 13, 14}}, found)
+}
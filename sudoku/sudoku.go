@@ -1,12 +1,15 @@
 package sudoku
 
 import (
+	"fmt"
 	"math"
 )
 
 type Board struct {
-	Size       int
+	Size       int // box side, 0 when the boxes aren't square
 	Size2      int
+	BoxRows    int
+	BoxCols    int
 	Candidates []bool // lit
 	Lookup     []int  // idx
 
@@ -14,25 +17,59 @@ type Board struct {
 	rowCandidateCount []int
 	colCandidateCount []int
 	blkCandidateCount []int
-	blkIdxMap         []int // idx -> blkIdx
+	blkIdxMap         []int   // idx -> blkIdx
+	Blocks            [][]int // blkIdx -> idx
+	irregular         bool    // blocks aren't BoxRows x BoxCols boxes
+	noBoxes           bool    // latin square, the blocks aren't houses
+
+	Houses              [][]int // extra houses, e.g. diagonals -> idx
+	houseCandidateCount []int
+	houseIdxMap         [][]int // idx -> extra houses, nil without extra houses
+
+	PairConstraints []PairConstraint
+	pairIdxMap      [][]int // idx -> pair constraints, nil without them
+
+	Cages      []Cage
+	cageIdxMap []int // idx -> cage index or -1, nil without cages
+
+	Lines []Line // thermometers, arrows, sandwiches
+
+	Inequalities []Inequality
+
+	Markers         []MarkerConstraint // kropki dots and XV
+	NegativeMarkers []Marker           // kinds whose absence is a constraint
 
 	lit_cLit []int // lit -> compressed lit, 1-indexed
 	cLit_lit []int // compressed lit -> lit, 1-indexed
 }
 
 func New(size int) *Board {
-	size2 := size * size
+	return NewRect(size, size)
+}
+
+// NewRect creates a board with boxRows x boxCols boxes, e.g. a 6x6 sudoku
+// with 2x3 boxes.
+func NewRect(boxRows, boxCols int) *Board {
+	size2 := boxRows * boxCols
+	size := boxRows
+	if boxRows != boxCols {
+		size = 0
+	}
 	candidates := make([]bool, size2*size2*size2+1)
 	rowCandidateCount := make([]int, size2*size2)
 	colCandidateCount := make([]int, size2*size2)
 	blkCandidateCount := make([]int, size2*size2)
 	blkIdxMap := make([]int, size2*size2)
+	blocks := make([][]int, size2)
 
 	board := &Board{
 		Size:       size,
 		Size2:      size2,
+		BoxRows:    boxRows,
+		BoxCols:    boxCols,
 		Lookup:     make([]int, size2*size2),
 		blkIdxMap:  blkIdxMap,
+		Blocks:     blocks,
 		Candidates: candidates,
 
 		rowCandidateCount: rowCandidateCount,
@@ -44,7 +81,9 @@ func New(size int) *Board {
 
 	for r := 0; r < size2; r++ {
 		for c := 0; c < size2; c++ {
-			blkIdxMap[r*size2+c] = r/size*size + c/size
+			blkIdx := r/boxRows*boxRows + c/boxCols
+			blkIdxMap[r*size2+c] = blkIdx
+			blocks[blkIdx] = append(blocks[blkIdx], r*size2+c)
 		}
 	}
 
@@ -61,6 +100,122 @@ func New(size int) *Board {
 	return board
 }
 
+// NewLatin creates an n x n latin square, where only rows and columns hold
+// distinct values.
+func NewLatin(n int) *Board {
+	board := NewRect(1, n)
+	board.noBoxes = true
+	return board
+}
+
+// SetBoxShape changes the boxes to boxRows x boxCols, which must multiply to
+// Size2. It has to be called before any value is set.
+func (b *Board) SetBoxShape(boxRows, boxCols int) error {
+	if boxRows < 1 || boxCols < 1 || boxRows*boxCols != b.Size2 {
+		return fmt.Errorf("%dx%d boxes don't fit a %dx%d grid", boxRows, boxCols, b.Size2, b.Size2)
+	}
+	regionMap := make([]int, len(b.Lookup))
+	for idx := range regionMap {
+		regionMap[idx] = idx/b.Size2/boxRows*boxRows + idx%b.Size2/boxCols
+	}
+	if err := b.SetRegions(regionMap); err != nil {
+		return err
+	}
+
+	b.irregular = false
+	b.BoxRows = boxRows
+	b.BoxCols = boxCols
+	b.Size = boxRows
+	if boxRows != boxCols {
+		b.Size = 0
+	}
+	return nil
+}
+
+// SetRegions replaces the boxes with arbitrary regions, as in
+// jigsaw sudoku. regionMap maps each idx to its region, numbered from 0, and
+// every region must have Size2 cells. It has to be called before any value is
+// set.
+func (b *Board) SetRegions(regionMap []int) error {
+	if len(regionMap) != len(b.Lookup) {
+		return fmt.Errorf("region map has %d cells, expected %d", len(regionMap), len(b.Lookup))
+	}
+	for _, val := range b.Lookup {
+		if val != 0 {
+			return fmt.Errorf("regions must be set before the values")
+		}
+	}
+
+	blocks := make([][]int, b.Size2)
+	for idx, blkIdx := range regionMap {
+		if blkIdx < 0 || blkIdx >= b.Size2 {
+			return fmt.Errorf("%s: region %d out of range", b.CellName(idx), blkIdx)
+		}
+		blocks[blkIdx] = append(blocks[blkIdx], idx)
+	}
+	for blkIdx, cells := range blocks {
+		if len(cells) != b.Size2 {
+			return fmt.Errorf("region %d has %d cells, expected %d", blkIdx, len(cells), b.Size2)
+		}
+	}
+
+	b.blkIdxMap = append([]int(nil), regionMap...)
+	b.Blocks = blocks
+	b.irregular = true
+	b.noBoxes = false
+
+	// candidates may already have been removed, e.g. by cages
+	for i := range b.blkCandidateCount {
+		b.blkCandidateCount[i] = 0
+	}
+	for idx, blkIdx := range b.blkIdxMap {
+		for v := 1; v <= b.Size2; v++ {
+			if b.Candidates[b.Lit(idx/b.Size2, idx%b.Size2, v)] {
+				b.blkCandidateCount[blkIdx*b.Size2+v-1]++
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegularBlocks reports whether the blocks are the standard size x size boxes.
+func (b *Board) RegularBlocks() bool {
+	return !b.irregular && !b.noBoxes
+}
+
+// RemoveBoxes turns the board into a latin square, where only rows and
+// columns hold distinct values. It has to be called before any value is set.
+func (b *Board) RemoveBoxes() error {
+	for _, val := range b.Lookup {
+		if val != 0 {
+			return fmt.Errorf("boxes must be removed before setting the values")
+		}
+	}
+	b.noBoxes = true
+	return nil
+}
+
+// HasBoxes reports whether the blocks are houses, i.e. it's not a latin
+// square.
+func (b *Board) HasBoxes() bool {
+	return !b.noBoxes
+}
+
+// Clone returns a deep copy of the board.
+func (b *Board) Clone() *Board {
+	clone := *b
+	clone.Candidates = append([]bool(nil), b.Candidates...)
+	clone.Lookup = append([]int(nil), b.Lookup...)
+	clone.rowCandidateCount = append([]int(nil), b.rowCandidateCount...)
+	clone.colCandidateCount = append([]int(nil), b.colCandidateCount...)
+	clone.blkCandidateCount = append([]int(nil), b.blkCandidateCount...)
+	clone.houseCandidateCount = append([]int(nil), b.houseCandidateCount...)
+	clone.lit_cLit = append([]int(nil), b.lit_cLit...)
+	clone.cLit_lit = append([]int(nil), b.cLit_lit...)
+	return &clone
+}
+
 func (b *Board) SetValue(row, col, val int) {
 	blkIndex := b.blkIdxMap[b.Idx(row, col)]
 
@@ -68,8 +223,6 @@ func (b *Board) SetValue(row, col, val int) {
 	b.rowCandidateCount[row*b.Size2+val-1] = 1
 	b.colCandidateCount[col*b.Size2+val-1] = 1
 	b.blkCandidateCount[blkIndex*b.Size2+val-1] = 1
-	blkRStart := b.Size * (row / b.Size)
-	blkCStart := b.Size * (col / b.Size)
 
 	for i := 0; i < b.Size2; i++ {
 		if i+1 != val {
@@ -83,12 +236,35 @@ func (b *Board) SetValue(row, col, val int) {
 		}
 	}
 
-	for r := 0; r < b.Size; r++ {
-		for c := 0; c < b.Size; c++ {
-			blkR := blkRStart + r
-			blkC := blkCStart + c
-			if blkR != row && blkC != col {
-				b.SetValueFalse(blkR, blkC, val)
+	for _, idx := range b.Blocks[blkIndex] {
+		blkR := idx / b.Size2
+		blkC := idx % b.Size2
+		if blkR != row && blkC != col && !b.noBoxes {
+			b.SetValueFalse(blkR, blkC, val)
+		}
+	}
+
+	if b.houseIdxMap != nil {
+		for _, house := range b.houseIdxMap[b.Idx(row, col)] {
+			b.houseCandidateCount[house*b.Size2+val-1] = 1
+			for _, idx := range b.Houses[house] {
+				if idx != b.Idx(row, col) {
+					b.SetValueFalse(idx/b.Size2, idx%b.Size2, val)
+				}
+			}
+		}
+	}
+
+	if b.pairIdxMap != nil {
+		for _, pair := range b.pairIdxMap[b.Idx(row, col)] {
+			b.eliminatePair(pair, b.Idx(row, col), val)
+		}
+	}
+
+	if b.cageIdxMap != nil && b.cageIdxMap[b.Idx(row, col)] >= 0 {
+		for _, idx := range b.Cages[b.cageIdxMap[b.Idx(row, col)]].Cells {
+			if idx != b.Idx(row, col) {
+				b.SetValueFalse(idx/b.Size2, idx%b.Size2, val)
 			}
 		}
 	}
@@ -104,6 +280,11 @@ func (b *Board) SetValueFalse(row, col, val int) {
 		b.rowCandidateCount[row*b.Size2+val-1] -= 1
 		b.colCandidateCount[col*b.Size2+val-1] -= 1
 		b.blkCandidateCount[blkIndex*b.Size2+val-1] -= 1
+		if b.houseIdxMap != nil {
+			for _, house := range b.houseIdxMap[b.Idx(row, col)] {
+				b.houseCandidateCount[house*b.Size2+val-1] -= 1
+			}
+		}
 	}
 }
 
@@ -113,6 +294,8 @@ func (b *Board) BasicSolve() {
 		restart = false
 		restart = restart || b.NakedSingles()
 		restart = restart || b.HiddenSingles()
+		restart = restart || b.PairSupport()
+		restart = restart || b.InequalityBounds()
 	}
 }
 
@@ -171,22 +354,35 @@ func (b *Board) HiddenSingles() bool {
 					}
 				}
 			}
-			if b.blkCandidateCount[i*b.Size2+v-1] == 1 {
-				blkRStart := (i / b.Size) * b.Size
-				blkCStart := (i % b.Size) * b.Size
-				for r := 0; r < b.Size; r++ {
-					for c := 0; c < b.Size; c++ {
-						// block
-						blkR := blkRStart + r
-						blkC := blkCStart + c
-						if b.Candidates[b.Lit(blkR, blkC, v)] {
-							if b.Lookup[b.Idx(blkR, blkC)] != v {
-								b.SetValue(blkR, blkC, v)
-								restart = true
-							}
-							break
+			if b.blkCandidateCount[i*b.Size2+v-1] == 1 && !b.noBoxes {
+				for _, idx := range b.Blocks[i] {
+					// block
+					blkR := idx / b.Size2
+					blkC := idx % b.Size2
+					if b.Candidates[b.Lit(blkR, blkC, v)] {
+						if b.Lookup[idx] != v {
+							b.SetValue(blkR, blkC, v)
+							restart = true
 						}
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for house, cells := range b.Houses {
+		for v := 1; v <= b.Size2; v++ {
+			if b.houseCandidateCount[house*b.Size2+v-1] != 1 {
+				continue
+			}
+			for _, idx := range cells {
+				if b.Candidates[b.Lit(idx/b.Size2, idx%b.Size2, v)] {
+					if b.Lookup[idx] != v {
+						b.SetValue(idx/b.Size2, idx%b.Size2, v)
+						restart = true
 					}
+					break
 				}
 			}
 		}
@@ -241,10 +437,17 @@ func (b *Board) Idx(row, col int) int {
 	return row*b.Size2 + col
 }
 
-func getSize(size2 int) int {
-	size := int(math.Sqrt(float64(size2)))
-	if size2 != size*size {
-		panic("Size is not a square")
+// CellName returns the 1-indexed r<row>c<col> name of a cell.
+func (b *Board) CellName(idx int) string {
+	return fmt.Sprintf("r%dc%d", idx/b.Size2+1, idx%b.Size2+1)
+}
+
+// BoxShape picks the most square boxRows x boxCols boxes for a size2 x size2
+// grid, with boxRows <= boxCols.
+func BoxShape(size2 int) (int, int) {
+	boxRows := int(math.Sqrt(float64(size2)))
+	for boxRows > 1 && size2%boxRows != 0 {
+		boxRows--
 	}
-	return size
+	return boxRows, size2 / boxRows
 }
@@ -13,3 +13,85 @@ func TestLit(t *testing.T) {
 	assert.Equal(t, 4, s.Lit(0, 0, 4))
 	assert.Equal(t, 64, s.Lit(3, 3, 4))
 }
+
+func TestClone(t *testing.T) {
+	s := New(2)
+	s.SetValue(0, 0, 1)
+	clone := s.Clone()
+	clone.SetValue(1, 1, 2)
+
+	assert.Equal(t, 1, clone.Lookup[clone.Idx(0, 0)])
+	assert.Equal(t, 0, s.Lookup[s.Idx(1, 1)])
+	assert.True(t, s.Candidates[s.Lit(1, 1, 3)])
+	assert.False(t, clone.Candidates[clone.Lit(1, 1, 3)])
+}
+
+func TestSetRegions(t *testing.T) {
+	s := New(2)
+	// two vertical dominoes per region
+	regions := []int{
+		0, 0, 1, 1,
+		0, 0, 1, 1,
+		2, 3, 2, 3,
+		2, 3, 2, 3,
+	}
+	assert.Error(t, s.SetRegions(regions[:8]))
+	assert.Error(t, s.SetRegions([]int{
+		0, 0, 0, 1,
+		0, 0, 1, 1,
+		2, 3, 2, 3,
+		2, 3, 2, 3,
+	}))
+	assert.NoError(t, s.SetRegions(regions))
+	assert.False(t, s.RegularBlocks())
+	assert.Equal(t, []int{9, 11, 13, 15}, s.Blocks[3])
+
+	s.SetValue(2, 1, 4)
+	assert.False(t, s.Candidates[s.Lit(3, 3, 4)])
+	assert.True(t, s.Candidates[s.Lit(3, 0, 4)])
+	assert.Error(t, s.SetRegions(regions))
+}
+
+func TestNewRect(t *testing.T) {
+	s := NewRect(2, 3)
+
+	assert.Equal(t, 6, s.Size2)
+	assert.Equal(t, 0, s.Size)
+	assert.True(t, s.RegularBlocks())
+	assert.Equal(t, []int{0, 1, 2, 6, 7, 8}, s.Blocks[0])
+	assert.Equal(t, []int{3, 4, 5, 9, 10, 11}, s.Blocks[1])
+	assert.Equal(t, []int{12, 13, 14, 18, 19, 20}, s.Blocks[2])
+
+	s.SetValue(0, 0, 1)
+	assert.False(t, s.Candidates[s.Lit(1, 2, 1)])
+	assert.True(t, s.Candidates[s.Lit(2, 1, 1)])
+
+	assert.NoError(t, NewRect(2, 3).SetBoxShape(3, 2))
+	assert.Error(t, NewRect(2, 3).SetBoxShape(2, 2))
+}
+
+func TestBoxShape(t *testing.T) {
+	for size2, shape := range map[int][2]int{
+		4:  {2, 2},
+		6:  {2, 3},
+		7:  {1, 7},
+		8:  {2, 4},
+		9:  {3, 3},
+		10: {2, 5},
+		12: {3, 4},
+		15: {3, 5},
+	} {
+		boxRows, boxCols := BoxShape(size2)
+		assert.Equal(t, shape, [2]int{boxRows, boxCols}, size2)
+	}
+}
+
+func TestNewLatin(t *testing.T) {
+	s := NewLatin(7)
+	assert.Equal(t, 7, s.Size2)
+	assert.False(t, s.HasBoxes())
+	s.SetValue(0, 0, 1)
+	assert.False(t, s.Candidates[s.Lit(0, 6, 1)])
+	assert.False(t, s.Candidates[s.Lit(6, 0, 1)])
+	assert.True(t, s.Candidates[s.Lit(1, 1, 1)])
+}
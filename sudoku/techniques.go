@@ -0,0 +1,352 @@
+package sudoku
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Technique is a human solving technique, see FindStep.
+type Technique int
+
+const (
+	NakedSingle Technique = iota
+	HiddenSingle
+	NakedPair
+	NakedTriple
+	NakedQuad
+	HiddenPair
+	HiddenTriple
+	HiddenQuad
+	Pointing
+	Claiming
+	XWing
+	Swordfish
+	Jellyfish
+	XYWing
+	XYZWing
+	WWing
+	SimpleColoring
+	XChain
+	AIC
+	PairRelation
+	InequalityBound
+	Search
+)
+
+var techniqueNames = []string{
+	"naked single",
+	"hidden single",
+	"naked pair",
+	"naked triple",
+	"naked quad",
+	"hidden pair",
+	"hidden triple",
+	"hidden quad",
+	"pointing",
+	"claiming",
+	"x-wing",
+	"swordfish",
+	"jellyfish",
+	"xy-wing",
+	"xyz-wing",
+	"w-wing",
+	"simple coloring",
+	"x-chain",
+	"aic",
+	"pair relation",
+	"inequality bound",
+	"search",
+}
+
+func (t Technique) String() string {
+	if t < 0 || int(t) >= len(techniqueNames) {
+		return "unknown"
+	}
+	return techniqueNames[t]
+}
+
+// Cell is a 0-indexed row and column.
+type Cell struct {
+	Row, Col int
+}
+
+func (c Cell) String() string {
+	return fmt.Sprintf("r%dc%d", c.Row+1, c.Col+1)
+}
+
+// Candidate is a value of a cell.
+type Candidate struct {
+	Cell
+	Value int
+}
+
+// Step is a deduction made by a technique: the cells and values of the
+// pattern it found, and the values it places or the candidates it
+// eliminates because of it.
+//
+// Chain techniques also give the chain, alternating strong links (one of
+// the two candidates is true) and weak links (at most one is true),
+// starting with a strong link. Simple coloring only uses strong links.
+type Step struct {
+	Technique    Technique
+	Cells        []Cell
+	Values       []int
+	Chain        []Candidate
+	Placements   []Candidate
+	Eliminations []Candidate
+}
+
+// Apply replays the step on b.
+func (s Step) Apply(b *Board) {
+	for _, p := range s.Placements {
+		b.SetValue(p.Row, p.Col, p.Value)
+	}
+	for _, e := range s.Eliminations {
+		b.SetValueFalse(e.Row, e.Col, e.Value)
+	}
+}
+
+// String describes the step, e.g.
+// naked pair r1c1 r1c2 {3 7}: r1c5<>3 r1c9<>7
+// x-chain r1c1 r1c5 r4c5 r4c8 {2} (2)r1c1=(2)r1c5-(2)r4c5=(2)r4c8: r1c8<>2
+func (s Step) String() string {
+	var sb strings.Builder
+	sb.WriteString(s.Technique.String())
+	for _, cell := range s.Cells {
+		sb.WriteString(" " + cell.String())
+	}
+	if len(s.Values) > 0 {
+		sb.WriteString(" {" + strings.Trim(fmt.Sprint(s.Values), "[]") + "}")
+	}
+	for i, c := range s.Chain {
+		switch {
+		case i == 0:
+			sb.WriteString(" ")
+		case i%2 == 1 || s.Technique == SimpleColoring:
+			sb.WriteString("=")
+		default:
+			sb.WriteString("-")
+		}
+		fmt.Fprintf(&sb, "(%d)%s", c.Value, c.Cell)
+	}
+	sb.WriteString(":")
+	for _, p := range s.Placements {
+		fmt.Fprintf(&sb, " %s=%d", p.Cell, p.Value)
+	}
+	for _, e := range s.Eliminations {
+		fmt.Fprintf(&sb, " %s<>%d", e.Cell, e.Value)
+	}
+	return sb.String()
+}
+
+// finders look for a step of their technique without changing the board,
+// simplest first.
+var finders = []func(b *Board) *Step{
+	findNakedSingle,
+	findHiddenSingle,
+	(*Board).findPairRelation,
+	(*Board).findInequalityBound,
+	findPointing,
+	findClaiming,
+	nakedSubsetFinder(2),
+	fishFinder(2),
+	hiddenSubsetFinder(2),
+	nakedSubsetFinder(3),
+	fishFinder(3),
+	hiddenSubsetFinder(3),
+	findXYWing,
+	findXYZWing,
+	findWWing,
+	findSimpleColoring,
+	nakedSubsetFinder(4),
+	fishFinder(4),
+	hiddenSubsetFinder(4),
+	findXChain,
+	findAIC,
+}
+
+// FindStep returns the simplest step the techniques can find on the board,
+// or nil if they're stuck. Besides the houses, only the pair constraints
+// and inequalities are taken into account, so the steps are sound with any
+// other constraint but may miss some.
+func (b *Board) FindStep() *Step {
+	for _, find := range finders {
+		if step := find(b); step != nil {
+			return step
+		}
+	}
+	return nil
+}
+
+// LogicalSolve applies the simplest step until the board is solved or the
+// techniques are stuck, returning the steps applied.
+func (b *Board) LogicalSolve() []Step {
+	var steps []Step
+	for {
+		step := b.FindStep()
+		if step == nil {
+			return steps
+		}
+		step.Apply(b)
+		steps = append(steps, *step)
+	}
+}
+
+// Solved reports whether every cell has a value.
+func (b *Board) Solved() bool {
+	for _, val := range b.Lookup {
+		if val == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Board) cell(idx int) Cell {
+	return Cell{idx / b.Size2, idx % b.Size2}
+}
+
+func (b *Board) candidate(idx, val int) Candidate {
+	return Candidate{b.cell(idx), val}
+}
+
+func (b *Board) isCandidate(idx, val int) bool {
+	return b.Lookup[idx] == 0 && b.Candidates[b.Lit(idx/b.Size2, idx%b.Size2, val)]
+}
+
+// candidateValues lists the candidates of an empty cell.
+func (b *Board) candidateValues(idx int) []int {
+	var values []int
+	for v := 1; v <= b.Size2; v++ {
+		if b.isCandidate(idx, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// allHouses lists the rows, the columns, the boxes unless it's a latin
+// square, and the extra houses, each as its cells (idx).
+func (b *Board) allHouses() [][]int {
+	var houses [][]int
+	for i := 0; i < b.Size2; i++ {
+		row := make([]int, b.Size2)
+		col := make([]int, b.Size2)
+		for j := 0; j < b.Size2; j++ {
+			row[j] = b.Idx(i, j)
+			col[j] = b.Idx(j, i)
+		}
+		houses = append(houses, row, col)
+	}
+	if b.HasBoxes() {
+		houses = append(houses, b.Blocks...)
+	}
+	return append(houses, b.Houses...)
+}
+
+// sees reports whether two different cells share a house.
+func (b *Board) sees(a, c int) bool {
+	if a == c {
+		return false
+	}
+	if a/b.Size2 == c/b.Size2 || a%b.Size2 == c%b.Size2 {
+		return true
+	}
+	if b.HasBoxes() && b.blkIdxMap[a] == b.blkIdxMap[c] {
+		return true
+	}
+	if b.houseIdxMap != nil {
+		for _, house := range b.houseIdxMap[a] {
+			for _, other := range b.houseIdxMap[c] {
+				if house == other {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func findNakedSingle(b *Board) *Step {
+	for idx, val := range b.Lookup {
+		if val != 0 {
+			continue
+		}
+		if values := b.candidateValues(idx); len(values) == 1 {
+			return &Step{
+				Technique:  NakedSingle,
+				Cells:      []Cell{b.cell(idx)},
+				Values:     values,
+				Placements: []Candidate{b.candidate(idx, values[0])},
+			}
+		}
+	}
+	return nil
+}
+
+func findHiddenSingle(b *Board) *Step {
+	for _, house := range b.allHouses() {
+		for v := 1; v <= b.Size2; v++ {
+			found, count := -1, 0
+			for _, idx := range house {
+				if b.Lookup[idx] == v {
+					count = -1
+					break
+				}
+				if b.isCandidate(idx, v) {
+					found = idx
+					count++
+				}
+			}
+			if count == 1 {
+				return &Step{
+					Technique:  HiddenSingle,
+					Cells:      []Cell{b.cell(found)},
+					Values:     []int{v},
+					Placements: []Candidate{b.candidate(found, v)},
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lockedSets calls fn with every n items, in order, whose sets have exactly
+// n members between them, and with that union in order, until fn returns
+// true. A partial choice is dropped as soon as its union has more than n
+// members, so few of the combinations are tried even on the largest boards.
+func lockedSets(items []int, sets [][]int, n int, fn func(subset, union []int) bool) bool {
+	subset := make([]int, 0, n)
+	var search func(from int, union []int) bool
+	search = func(from int, union []int) bool {
+		if len(subset) == n {
+			return len(union) == n && fn(subset, union)
+		}
+		for i := from; i <= len(items)-(n-len(subset)); i++ {
+			merged := mergeSets(union, sets[i])
+			if len(merged) > n {
+				continue
+			}
+			subset = append(subset, items[i])
+			if search(i+1, merged) {
+				return true
+			}
+			subset = subset[:len(subset)-1]
+		}
+		return false
+	}
+	return search(0, nil)
+}
+
+// mergeSets returns the members of a or b, in order.
+func mergeSets(a, b []int) []int {
+	union := append([]int(nil), a...)
+	for _, x := range b {
+		if !contains(union, x) {
+			union = append(union, x)
+		}
+	}
+	sort.Ints(union)
+	return union
+}
@@ -0,0 +1,57 @@
+package sudoku
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSingles(t *testing.T) {
+	s := New(2)
+	s.SetValue(0, 0, 1)
+	s.SetValue(0, 1, 2)
+	s.SetValue(0, 2, 3)
+	step := s.FindStep()
+	assert.Equal(t, &Step{
+		Technique:  NakedSingle,
+		Cells:      []Cell{{0, 3}},
+		Values:     []int{4},
+		Placements: []Candidate{{Cell{0, 3}, 4}},
+	}, step)
+	assert.Equal(t, "naked single r1c4 {4}: r1c4=4", step.String())
+
+	s = New(2)
+	s.SetValue(1, 0, 1)
+	s.SetValue(2, 1, 1)
+	s.SetValue(3, 2, 1)
+	step = s.FindStep()
+	assert.Equal(t, HiddenSingle, step.Technique)
+	assert.Equal(t, []Candidate{{Cell{0, 3}, 1}}, step.Placements)
+}
+
+func TestLogicalSolve(t *testing.T) {
+	const solution = "247639851186425397395187264532761489619842735874593612768914523421358976953276148"
+	input, _ := ioutil.ReadFile("../data/sudoku-hyper-9-1.txt")
+	s := NewFromString(string(input))
+	steps := s.LogicalSolve()
+	assert.NotEmpty(t, steps)
+	assert.Nil(t, s.FindStep())
+	for idx, val := range s.Lookup {
+		if val != 0 {
+			assert.Equal(t, int(solution[idx]-'0'), val)
+		}
+	}
+
+	replayed := NewFromString(string(input))
+	for _, step := range steps {
+		step.Apply(replayed)
+	}
+	assert.Equal(t, s.Lookup, replayed.Lookup)
+	assert.Equal(t, s.Candidates, replayed.Candidates)
+
+	input, _ = ioutil.ReadFile("../data/sudoku-16-1.txt")
+	s = NewFromString(string(input))
+	s.LogicalSolve()
+	assert.True(t, s.Solved())
+}
@@ -0,0 +1,189 @@
+package sudoku
+
+import "sort"
+
+// findXYWing finds a pivot cell {x y} seeing two pincers {x z} and {y z}:
+// either way one pincer is z, so z can't be in a cell seeing both.
+func findXYWing(b *Board) *Step {
+	bivalues := b.bivalueCells()
+	for _, pivot := range bivalues {
+		xy := b.candidateValues(pivot)
+		for _, p1 := range bivalues {
+			if !b.sees(pivot, p1) {
+				continue
+			}
+			x, z := sharedValue(xy, b.candidateValues(p1))
+			if x == 0 {
+				continue
+			}
+			y := xy[0] + xy[1] - x
+			for _, p2 := range bivalues {
+				if p2 == p1 || !b.sees(pivot, p2) || !b.isCandidate(p2, y) || !b.isCandidate(p2, z) {
+					continue
+				}
+				eliminations := b.seenByAll(z, p1, p2)
+				if len(eliminations) == 0 {
+					continue
+				}
+				return &Step{
+					Technique: XYWing,
+					Cells:     b.cells([]int{pivot, p1, p2}),
+					Values:    sortedValues(x, y, z),
+					Chain: []Candidate{
+						b.candidate(p1, z), b.candidate(p1, x),
+						b.candidate(pivot, x), b.candidate(pivot, y),
+						b.candidate(p2, y), b.candidate(p2, z),
+					},
+					Eliminations: eliminations,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findXYZWing finds a pivot cell {x y z} seeing two pincers {x z} and
+// {y z}: one of the three is z, so z can't be in a cell seeing all of them.
+func findXYZWing(b *Board) *Step {
+	bivalues := b.bivalueCells()
+	for pivot := range b.Lookup {
+		xyz := b.candidateValues(pivot)
+		if len(xyz) != 3 {
+			continue
+		}
+		for i, p1 := range bivalues {
+			xz := b.candidateValues(p1)
+			if !b.sees(pivot, p1) || !contains(xyz, xz[0]) || !contains(xyz, xz[1]) {
+				continue
+			}
+			for _, p2 := range bivalues[i+1:] {
+				yz := b.candidateValues(p2)
+				if !b.sees(pivot, p2) || !contains(xyz, yz[0]) || !contains(xyz, yz[1]) {
+					continue
+				}
+				z, _ := sharedValue(yz, xz)
+				if z == 0 {
+					continue
+				}
+				eliminations := b.seenByAll(z, pivot, p1, p2)
+				if len(eliminations) == 0 {
+					continue
+				}
+				return &Step{
+					Technique:    XYZWing,
+					Cells:        b.cells([]int{pivot, p1, p2}),
+					Values:       xyz,
+					Eliminations: eliminations,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findWWing finds two cells {x y} that don't see each other, joined by a
+// strong link on x: if one isn't y, it's x and the other must be y, so y
+// can't be in a cell seeing both.
+func findWWing(b *Board) *Step {
+	bivalues := b.bivalueCells()
+	houses := b.allHouses()
+	for i, c1 := range bivalues {
+		xy := b.candidateValues(c1)
+		for _, c2 := range bivalues[i+1:] {
+			if b.sees(c1, c2) || !equalValues(xy, b.candidateValues(c2)) {
+				continue
+			}
+			for _, x := range xy {
+				y := xy[0] + xy[1] - x
+				eliminations := b.seenByAll(y, c1, c2)
+				if len(eliminations) == 0 {
+					continue
+				}
+				for _, house := range houses {
+					pair := b.positions(house, x)
+					if len(pair) != 2 || contains(pair, c1) || contains(pair, c2) {
+						continue
+					}
+					p, q := pair[0], pair[1]
+					if !b.sees(p, c1) || !b.sees(q, c2) {
+						p, q = q, p
+					}
+					if !b.sees(p, c1) || !b.sees(q, c2) {
+						continue
+					}
+					return &Step{
+						Technique: WWing,
+						Cells:     b.cells([]int{c1, p, q, c2}),
+						Values:    xy,
+						Chain: []Candidate{
+							b.candidate(c1, y), b.candidate(c1, x),
+							b.candidate(p, x), b.candidate(q, x),
+							b.candidate(c2, x), b.candidate(c2, y),
+						},
+						Eliminations: eliminations,
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// bivalueCells lists the empty cells with exactly two candidates.
+func (b *Board) bivalueCells() []int {
+	var cells []int
+	for idx := range b.Lookup {
+		if len(b.candidateValues(idx)) == 2 {
+			cells = append(cells, idx)
+		}
+	}
+	return cells
+}
+
+// sharedValue returns the value of pair also in values and the other one,
+// or zeros unless exactly one is.
+func sharedValue(values, pair []int) (shared, other int) {
+	switch {
+	case contains(values, pair[0]) && !contains(values, pair[1]):
+		return pair[0], pair[1]
+	case contains(values, pair[1]) && !contains(values, pair[0]):
+		return pair[1], pair[0]
+	}
+	return 0, 0
+}
+
+// seenByAll lists the candidates for v in cells seeing all of the given
+// cells.
+func (b *Board) seenByAll(v int, cells ...int) []Candidate {
+	var eliminations []Candidate
+	for idx := range b.Lookup {
+		if !b.isCandidate(idx, v) {
+			continue
+		}
+		seen := true
+		for _, c := range cells {
+			seen = seen && b.sees(idx, c)
+		}
+		if seen {
+			eliminations = append(eliminations, b.candidate(idx, v))
+		}
+	}
+	return eliminations
+}
+
+func sortedValues(values ...int) []int {
+	sort.Ints(values)
+	return values
+}
+
+func equalValues(a, c []int) bool {
+	if len(a) != len(c) {
+		return false
+	}
+	for i := range a {
+		if a[i] != c[i] {
+			return false
+		}
+	}
+	return true
+}
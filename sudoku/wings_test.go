@@ -0,0 +1,41 @@
+package sudoku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXYWing(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 0), []int{1, 2}))
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 4), []int{1, 3}))
+	assert.NoError(t, s.RestrictCell(s.Idx(4, 0), []int{2, 3}))
+	step := s.FindStep()
+	assert.Equal(t, XYWing, step.Technique)
+	assert.Equal(t, "xy-wing r1c1 r1c5 r5c1 {1 2 3} (3)r1c5=(1)r1c5-(1)r1c1=(2)r1c1-(2)r5c1=(3)r5c1: r5c5<>3", step.String())
+}
+
+func TestXYZWing(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 0), []int{1, 2, 3}))
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 4), []int{1, 3}))
+	assert.NoError(t, s.RestrictCell(s.Idx(1, 1), []int{2, 3}))
+	step := s.FindStep()
+	assert.Equal(t, XYZWing, step.Technique)
+	assert.Equal(t, []Cell{{0, 0}, {0, 4}, {1, 1}}, step.Cells)
+	assert.Equal(t, []Candidate{{Cell{0, 1}, 3}, {Cell{0, 2}, 3}}, step.Eliminations)
+}
+
+func TestWWing(t *testing.T) {
+	s := New(3)
+	assert.NoError(t, s.RestrictCell(s.Idx(0, 0), []int{1, 2}))
+	assert.NoError(t, s.RestrictCell(s.Idx(8, 8), []int{1, 2}))
+	keepOnly(s, 4, 1, 0, 8)
+	step := s.FindStep()
+	assert.Equal(t, WWing, step.Technique)
+	assert.Equal(t, "w-wing r1c1 r5c1 r5c9 r9c9 {1 2} (2)r1c1=(1)r1c1-(1)r5c1=(1)r5c9-(1)r9c9=(2)r9c9: r1c9<>2 r9c1<>2", step.String())
+
+	step.Apply(s)
+	assert.Nil(t, findWWing(s))
+}
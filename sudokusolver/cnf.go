@@ -1,6 +1,7 @@
 package sudokusolver
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/irifrance/gini"
@@ -14,21 +15,46 @@ type CNFInterface interface {
 	addFormula(lits []int, builder CNFBuilder)
 	requestLiterals(num uint32) []int
 	getBoard() *sudoku.Board
-	Print(w io.Writer)
+	getOptions() *EncoderOptions
+	Print(w io.Writer) error
+}
+
+// ClauseSink receives clauses as DIMACS literals, with 0 ending a clause.
+// Sinks that can also serialize the formula implement Write(io.Writer) error.
+type ClauseSink interface {
+	Add(lit int)
+}
+
+type giniSink struct {
+	g *gini.Gini
+}
+
+// GiniSink adapts a gini solver to a ClauseSink.
+func GiniSink(g *gini.Gini) ClauseSink {
+	return giniSink{g}
+}
+
+func (s giniSink) Add(lit int) {
+	s.g.Add(z.Dimacs2Lit(lit))
+}
+
+func (s giniSink) Write(w io.Writer) error {
+	return s.g.Write(w)
 }
 
 type CNF struct {
 	CNFInterface
-	g     *gini.Gini
+	sink  ClauseSink
 	Board *sudoku.Board
+	opts  EncoderOptions
 	nbVar uint32
 }
 
 type CNFBuilder = func(c CNFInterface, lits []int)
 
 func (c *CNF) addLit(lit int) {
-	c.g.Add(z.Dimacs2Lit(lit))
-	c.g.Add(0)
+	c.sink.Add(lit)
+	c.sink.Add(0)
 }
 
 func (c *CNF) requestLiterals(num uint32) []int {
@@ -39,9 +65,9 @@ func (c *CNF) requestLiterals(num uint32) []int {
 
 func (c *CNF) addClause(clause []int) {
 	for _, lit := range clause {
-		c.g.Add(z.Dimacs2Lit(lit))
+		c.sink.Add(lit)
 	}
-	c.g.Add(0)
+	c.sink.Add(0)
 }
 
 func (c *CNF) addFormula(lits []int, builder CNFBuilder) {
@@ -52,6 +78,16 @@ func (c *CNF) getBoard() *sudoku.Board {
 	return c.Board
 }
 
-func (c *CNF) Print(w io.Writer) {
-	c.g.Write(w)
+func (c *CNF) getOptions() *EncoderOptions {
+	return &c.opts
+}
+
+// Print writes the formula in DIMACS format, which only sinks implementing
+// Write(io.Writer) error can do.
+func (c *CNF) Print(w io.Writer) error {
+	writer, ok := c.sink.(interface{ Write(io.Writer) error })
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrNotPrintable, c.sink)
+	}
+	return writer.Write(w)
 }
@@ -5,12 +5,29 @@ func cnfAtLeast1(c CNFInterface, lits []int) {
 }
 
 func cnfAtMost1(c CNFInterface, lits []int) {
-	_cnfAtMost1(c, lits, false)
+	opts := c.getOptions()
+	switch opts.AtMostOne {
+	case PairwiseAtMostOne:
+		cnfAtMost1Pairwise(c, lits)
+	case CommanderAtMostOne:
+		cnfAtMost1Commander(c, lits)
+	case BimanderAtMostOne:
+		cnfAtMost1Bimander(c, lits)
+	case BinaryAtMostOne:
+		cnfAtMost1Binary(c, lits)
+	case SequentialAtMostOne:
+		cnfAtMost1Sequential(c, lits)
+	case LadderAtMostOne:
+		cnfAtMost1Ladder(c, lits)
+	case ProductAtMostOne:
+		cnfAtMost1Product(c, lits)
+	default:
+		cnfAtMost1Auto(c, lits)
+	}
 }
 
-func _cnfAtMost1(c CNFInterface, lits []int, pairwise bool) {
-	// cnfAtMost1Pairwise(c, lits)
-	if pairwise || len(lits) <= 5 {
+func cnfAtMost1Auto(c CNFInterface, lits []int) {
+	if len(lits) <= 5 {
 		cnfAtMost1Pairwise(c, lits)
 		return
 	}
@@ -50,17 +67,18 @@ const COMMANDER_FACTOR = 3
 func cnfAtMost1Commander(c CNFInterface, lits []int) {
 	n := len(lits)
 	if n <= 3 {
-		_cnfAtMost1(c, lits, true)
+		cnfAtMost1Pairwise(c, lits)
 		return
 	}
-	m := (n + COMMANDER_FACTOR - 1) / COMMANDER_FACTOR
+	groupSize := groupSizeOr(c.getOptions().CommanderGroupSize, COMMANDER_FACTOR)
+	m := (n + groupSize - 1) / groupSize
 	groupLen := (n + m - 1) / m
 
 	groups := make([][]int, m)
 	for i := 0; i < m; i++ {
 		groups[i] = lits[groupLen*i : min(groupLen*(i+1), n)]
 		// 1. At most one variable in a group can be true
-		_cnfAtMost1(c, groups[i], true)
+		cnfAtMost1Pairwise(c, groups[i])
 	}
 
 	commanders := c.requestLiterals(uint32(m))
@@ -85,14 +103,18 @@ const BIMANDER_FACTOR = 2
 // A new method to encode the at-most-one constraint into SAT.
 func cnfAtMost1Bimander(c CNFInterface, lits []int) {
 	n := len(lits)
-	m := (n + BIMANDER_FACTOR - 1) / BIMANDER_FACTOR
+	if n <= 1 {
+		return
+	}
+	groupSize := groupSizeOr(c.getOptions().BimanderGroupSize, BIMANDER_FACTOR)
+	m := (n + groupSize - 1) / groupSize
 	groupLen := (n + m - 1) / m
 	binLength := getBinLength(uint32(m))
 
 	groups := make([][]int, m)
 	for i := 0; i < m; i++ {
 		groups[i] = lits[groupLen*i : min(groupLen*(i+1), n)]
-		_cnfAtMost1(c, groups[i], true)
+		cnfAtMost1Pairwise(c, groups[i])
 	}
 
 	auxVars := c.requestLiterals(binLength)
@@ -111,6 +133,111 @@ func cnfAtMost1Bimander(c CNFInterface, lits []int) {
 	}
 }
 
+// Frisch, Alan M., et al. 2005.
+// The Essence of ESSENCE. (binary / logarithmic encoding)
+func cnfAtMost1Binary(c CNFInterface, lits []int) {
+	n := len(lits)
+	if n <= 1 {
+		return
+	}
+	bits := c.requestLiterals(getBitLength(uint32(n - 1)))
+
+	for i, lit := range lits {
+		for j, bit := range bits {
+			if (i & (1 << j)) == 0 {
+				bit = -bit
+			}
+			// lit -> the bits spell out i
+			cnfAtLeast1(c, []int{-lit, bit})
+		}
+	}
+}
+
+// Sinz, Carsten. 2005.
+// Towards an optimal CNF encoding of boolean cardinality constraints.
+func cnfAtMost1Sequential(c CNFInterface, lits []int) {
+	n := len(lits)
+	if n <= 1 {
+		return
+	}
+	// s[i] is true if any of lits[0..i] is true
+	s := c.requestLiterals(uint32(n - 1))
+
+	cnfAtLeast1(c, []int{-lits[0], s[0]})
+	for i := 1; i < n-1; i++ {
+		cnfAtLeast1(c, []int{-lits[i], s[i]})
+		cnfAtLeast1(c, []int{-s[i-1], s[i]})
+		cnfAtLeast1(c, []int{-lits[i], -s[i-1]})
+	}
+	cnfAtLeast1(c, []int{-lits[n-1], -s[n-2]})
+}
+
+// Gent, Ian P., and Peter Nightingale. 2004.
+// A new encoding of AllDifferent into SAT.
+func cnfAtMost1Ladder(c CNFInterface, lits []int) {
+	n := len(lits)
+	if n <= 1 {
+		return
+	}
+	// y[i] is true if any of lits[i+1..n-1] is true
+	y := c.requestLiterals(uint32(n - 1))
+
+	for i := 0; i < n-2; i++ {
+		// y[i+1] -> y[i]
+		cnfAtLeast1(c, []int{-y[i+1], y[i]})
+	}
+	for i, lit := range lits {
+		if i > 0 {
+			cnfAtLeast1(c, []int{-lit, y[i-1]})
+		}
+		if i < n-1 {
+			cnfAtLeast1(c, []int{-lit, -y[i]})
+		}
+	}
+}
+
+// Chen, Jingchao. 2010.
+// A new SAT encoding of the at-most-one constraint.
+func cnfAtMost1Product(c CNFInterface, lits []int) {
+	n := len(lits)
+	if n <= 4 {
+		cnfAtMost1Pairwise(c, lits)
+		return
+	}
+	p := 1
+	for p*p < n {
+		p++
+	}
+	q := (n + p - 1) / p
+
+	rows := c.requestLiterals(uint32(p))
+	cols := c.requestLiterals(uint32(q))
+	for i, lit := range lits {
+		cnfAtLeast1(c, []int{-lit, rows[i/q]})
+		cnfAtLeast1(c, []int{-lit, cols[i%q]})
+	}
+
+	cnfAtMost1Product(c, rows)
+	cnfAtMost1Product(c, cols)
+}
+
+func groupSizeOr(size, fallback int) int {
+	if size < 2 {
+		return fallback
+	}
+	return size
+}
+
+// number of bits needed to represent m
+func getBitLength(m uint32) uint32 {
+	len := uint32(0)
+	for m > 0 {
+		len++
+		m >>= 1
+	}
+	return len
+}
+
 func getBinLength(m uint32) uint32 {
 	len := uint32(1)
 	for m > len {
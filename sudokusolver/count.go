@@ -0,0 +1,79 @@
+package sudokusolver
+
+import (
+	"github.com/irifrance/gini"
+	"github.com/irifrance/gini/z"
+	"github.com/rkkautsar/sudoku-solver/sudoku"
+)
+
+// CountSolutions counts the solutions of board, stopping once limit solutions
+// are found (limit <= 0 counts all of them). The board is left filled with the
+// first solution found, if any.
+func CountSolutions(board *sudoku.Board, limit int, opts EncoderOptions) (int, error) {
+	count := 0
+	err := forEachModel(board, opts, func(model []bool) bool {
+		if count == 0 {
+			board.SolveWithModel(model)
+		}
+		count++
+		return limit <= 0 || count < limit
+	})
+	return count, err
+}
+
+// IsUnique reports whether board has exactly one solution. The board is left
+// filled with the first solution found, if any.
+func IsUnique(board *sudoku.Board, opts EncoderOptions) (bool, error) {
+	count, err := CountSolutions(board, 2, opts)
+	return count == 1, err
+}
+
+// forEachModel calls fn with every model of the board's CNF, blocking each
+// model before searching for the next one, until fn returns false or the
+// formula becomes unsatisfiable.
+func forEachModel(board *sudoku.Board, opts EncoderOptions, fn func(model []bool) bool) error {
+	board.BasicSolve()
+	g := gini.NewVc(2*board.NumCandidates, 3*board.NumCandidates)
+	cnf := GenerateCNFConstraints(board, GiniSink(g), opts)
+
+	for {
+		status := g.Solve()
+		if status < 0 {
+			return nil
+		}
+		if status == 0 {
+			return ErrUnknown
+		}
+
+		model := giniModel(g, board.NumCandidates)
+		if !fn(model) {
+			return nil
+		}
+		blockModel(cnf, model)
+	}
+}
+
+func giniModel(g *gini.Gini, numCandidates int) []bool {
+	return readModel(numCandidates, func(lit int) bool {
+		return g.Value(z.Dimacs2Lit(lit))
+	})
+}
+
+func readModel(numCandidates int, value func(lit int) bool) []bool {
+	model := make([]bool, numCandidates)
+	for i := 1; i <= len(model); i++ {
+		model[i-1] = value(i)
+	}
+	return model
+}
+
+// blockModel forbids the candidate assignment of model from being found again.
+func blockModel(c CNFInterface, model []bool) {
+	clause := make([]int, 0, c.getBoard().Size2*c.getBoard().Size2)
+	for i, value := range model {
+		if value {
+			clause = append(clause, -(i + 1))
+		}
+	}
+	c.addClause(clause)
+}
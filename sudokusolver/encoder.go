@@ -1,32 +1,53 @@
 package sudokusolver
 
 import (
-	"github.com/irifrance/gini"
 	"github.com/rkkautsar/sudoku-solver/sudoku"
 )
 
-func GenerateCNFConstraints(s *sudoku.Board, g *gini.Gini) CNFInterface {
+func GenerateCNFConstraints(s *sudoku.Board, sink ClauseSink, opts EncoderOptions) CNFInterface {
 	var cnf CNFInterface
 
 	s.InitCompressedLits()
 	cnf = &CNF{
 		Board: s,
-		g:     g,
+		sink:  sink,
+		opts:  opts,
 		nbVar: uint32(s.NumCandidates),
 	}
-	initializeLits(cnf)
-	buildCNFCellConstraints(cnf, cnfExactly1)
-	buildCNFRangeConstraints(cnf, cnfExactly1)
+	buildCNF(cnf)
 
 	return cnf
 }
 
+func buildCNF(cnf CNFInterface) {
+	initializeLits(cnf)
+	buildCNFCellConstraints(cnf, cnfExactly1)
+	switch {
+	case cnf.getOptions().Encoding == TriadEncoding && cnf.getBoard().RegularBlocks():
+		buildCNFTriadConstraints(cnf, cnfExactly1)
+	default:
+		buildCNFRangeConstraints(cnf, cnfExactly1)
+	}
+	buildCNFHouseConstraints(cnf, cnfExactly1)
+	buildCNFCageConstraints(cnf, cnfExactly1)
+	buildCNFPairConstraints(cnf)
+	buildCNFLineConstraints(cnf)
+	buildCNFInequalityConstraints(cnf)
+}
+
 func initializeLits(c CNFInterface) {
 	b := c.getBoard()
 	for i := 0; i < len(b.Lookup); i++ {
-		if b.Lookup[i] != 0 {
-			c.addLit(b.CLit(i/b.Size2, i%b.Size2, b.Lookup[i]))
+		if b.Lookup[i] == 0 {
+			continue
+		}
+		lit := b.CLit(i/b.Size2, i%b.Size2, b.Lookup[i])
+		if lit == 0 {
+			// the given was eliminated by another given, so the puzzle is UNSAT
+			c.addClause([]int{})
+			continue
 		}
+		c.addLit(lit)
 	}
 }
 
@@ -54,22 +75,18 @@ func buildCNFRangeConstraints(
 	builder CNFBuilder,
 ) {
 	b := c.getBoard()
-	size := b.Size
 	size2 := b.Size2
 
 	for v := 1; v <= size2; v++ {
 		for i := 0; i < size2; i++ {
 
-			blkRowStart := (i / size) * size
-			blkColStart := (i % size) * size
 			rowLits := make([]int, size2)
 			colLits := make([]int, size2)
 			blkLits := make([]int, size2)
 			for j := 0; j < size2; j++ {
 				// block
-				blkRow := blkRowStart + j/size
-				blkCol := blkColStart + j%size
-				blkLits[j] = b.CLit(blkRow, blkCol, v)
+				blkIdx := b.Blocks[i][j]
+				blkLits[j] = b.CLit(blkIdx/size2, blkIdx%size2, v)
 
 				// row
 				rowLits[j] = b.CLit(i, j, v)
@@ -78,13 +95,104 @@ func buildCNFRangeConstraints(
 				colLits[j] = b.CLit(j, i, v)
 			}
 
-			c.addFormula(filterZero(blkLits), builder)
+			if b.HasBoxes() {
+				c.addFormula(filterZero(blkLits), builder)
+			}
 			c.addFormula(filterZero(rowLits), builder)
 			c.addFormula(filterZero(colLits), builder)
 		}
 	}
 }
 
+// extra houses, e.g. the diagonals of Sudoku-X
+func buildCNFHouseConstraints(
+	c CNFInterface,
+	builder CNFBuilder,
+) {
+	b := c.getBoard()
+	size2 := b.Size2
+
+	for _, house := range b.Houses {
+		for v := 1; v <= size2; v++ {
+			lits := make([]int, size2)
+			for j, idx := range house {
+				lits[j] = b.CLit(idx/size2, idx%size2, v)
+			}
+			c.addFormula(filterZero(lits), builder)
+		}
+	}
+}
+
+// Triads are the cells shared by a row and a box (band triads) or by a column
+// and a box (stack triads). A triad literal is true iff the triad holds
+// the value, so each row and column has exactly one triad with the value and
+// each box has exactly one band triad and one stack triad with it.
+func buildCNFTriadConstraints(
+	c CNFInterface,
+	builder CNFBuilder,
+) {
+	b := c.getBoard()
+	boxRows := b.BoxRows
+	boxCols := b.BoxCols
+	size2 := b.Size2
+
+	for v := 1; v <= size2; v++ {
+		// bandTriads[r][k] is the triad of row r in the k-th box of its band,
+		// stackTriads[col][k] the triad of column col in the k-th box of its stack
+		bandTriads := make([][]int, size2)
+		stackTriads := make([][]int, size2)
+		for i := 0; i < size2; i++ {
+			bandTriads[i] = c.requestLiterals(uint32(boxRows))
+			for k := 0; k < boxRows; k++ {
+				bandLits := make([]int, boxCols)
+				for j := 0; j < boxCols; j++ {
+					bandLits[j] = b.CLit(i, k*boxCols+j, v)
+				}
+				buildCNFTriad(c, bandTriads[i][k], filterZero(bandLits))
+			}
+
+			stackTriads[i] = c.requestLiterals(uint32(boxCols))
+			for k := 0; k < boxCols; k++ {
+				stackLits := make([]int, boxRows)
+				for j := 0; j < boxRows; j++ {
+					stackLits[j] = b.CLit(k*boxRows+j, i, v)
+				}
+				buildCNFTriad(c, stackTriads[i][k], filterZero(stackLits))
+			}
+
+			// row / col
+			c.addFormula(bandTriads[i], builder)
+			c.addFormula(stackTriads[i], builder)
+		}
+
+		// block, there are boxRows boxes in a band
+		for i := 0; i < size2; i++ {
+			blkRowStart := (i / boxRows) * boxRows
+			blkColStart := (i % boxRows) * boxCols
+			bandLits := make([]int, boxRows)
+			stackLits := make([]int, boxCols)
+			for j := 0; j < boxRows; j++ {
+				bandLits[j] = bandTriads[blkRowStart+j][i%boxRows]
+			}
+			for j := 0; j < boxCols; j++ {
+				stackLits[j] = stackTriads[blkColStart+j][i/boxRows]
+			}
+			c.addFormula(bandLits, builder)
+			c.addFormula(stackLits, builder)
+		}
+	}
+}
+
+// triad <-> any of lits, with at most one of lits true
+func buildCNFTriad(c CNFInterface, triad int, lits []int) {
+	clause := append([]int{-triad}, lits...)
+	c.addClause(clause)
+	for _, lit := range lits {
+		c.addClause([]int{-lit, triad})
+	}
+	cnfAtMost1(c, lits)
+}
+
 func filterZero(slice []int) []int {
 	newSlice := slice[:0]
 	for _, x := range slice {
@@ -0,0 +1,56 @@
+package sudokusolver
+
+import (
+	"context"
+
+	"github.com/rkkautsar/sudoku-solver/sudoku"
+)
+
+// EnumerateSolutions streams the solutions of board as they are found, up to
+// limit solutions (limit <= 0 for all of them). The solutions channel is
+// closed when the search is exhausted, the limit is reached or ctx is
+// cancelled; the error channel then receives the reason the search stopped
+// early, if any, and is closed.
+//
+// The search runs on board itself, which must not be used until the solutions
+// channel is closed.
+func EnumerateSolutions(
+	ctx context.Context,
+	board *sudoku.Board,
+	limit int,
+	opts EncoderOptions,
+) (<-chan *sudoku.Board, <-chan error) {
+	solutions := make(chan *sudoku.Board)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(solutions)
+
+		count := 0
+		var cancelled error
+		err := forEachModel(board, opts, func(model []bool) bool {
+			if cancelled = ctx.Err(); cancelled != nil {
+				return false
+			}
+			solution := board.Clone()
+			solution.SolveWithModel(model)
+			select {
+			case solutions <- solution:
+			case <-ctx.Done():
+				cancelled = ctx.Err()
+				return false
+			}
+			count++
+			return limit <= 0 || count < limit
+		})
+		if err == nil {
+			err = cancelled
+		}
+		if err != nil {
+			errc <- err
+		}
+	}()
+
+	return solutions, errc
+}
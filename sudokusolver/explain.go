@@ -0,0 +1,19 @@
+package sudokusolver
+
+import "github.com/rkkautsar/sudoku-solver/sudoku"
+
+// Explain solves the board step by step with the techniques, recording the
+// path. Whatever they can't deduce is then found with gini and recorded as a
+// search step.
+func Explain(board *sudoku.Board, opts EncoderOptions) (*sudoku.Path, error) {
+	path := board.SolvePath()
+	if board.Solved() {
+		return path, nil
+	}
+	solution := board.Clone()
+	if _, err := SolveWithGini(solution, opts); err != nil {
+		return path, err
+	}
+	path.RecordSearch(board, solution)
+	return path, nil
+}
@@ -0,0 +1,53 @@
+package sudokusolver
+
+// Inequalities go through the order encoding of their cells: ge[v] is true
+// iff the cell's value is at least v, so less < greater becomes
+// less >= v -> greater >= v+1.
+func buildCNFInequalityConstraints(c CNFInterface) {
+	b := c.getBoard()
+	if len(b.Inequalities) == 0 {
+		return
+	}
+
+	t := cnfTrue(c)
+	order := map[int][]int{}
+	orderOf := func(idx int) []int {
+		if _, ok := order[idx]; !ok {
+			order[idx] = cnfOrder(c, t, idx)
+		}
+		return order[idx]
+	}
+
+	for _, ineq := range b.Inequalities {
+		less, greater := orderOf(ineq.Less), orderOf(ineq.Greater)
+		for v := 1; v <= b.Size2; v++ {
+			c.addClause([]int{-less[v], greater[v+1]})
+		}
+	}
+}
+
+// cnfOrder returns the order encoding ge[1..Size2+1] of the cell idx, with
+// ge[1] = t and ge[Size2+1] = -t, channelled to its value literals.
+func cnfOrder(c CNFInterface, t, idx int) []int {
+	b := c.getBoard()
+	ge := make([]int, b.Size2+2)
+	ge[1], ge[b.Size2+1] = t, -t
+	copy(ge[2:], c.requestLiterals(uint32(b.Size2-1)))
+
+	for v := 2; v <= b.Size2; v++ {
+		// ge[v] -> ge[v-1]
+		c.addClause([]int{-ge[v], ge[v-1]})
+	}
+	for v := 1; v <= b.Size2; v++ {
+		// lit <-> ge[v] and not ge[v+1]
+		lit := b.CLit(idx/b.Size2, idx%b.Size2, v)
+		if lit == 0 {
+			c.addClause([]int{-ge[v], ge[v+1]})
+			continue
+		}
+		c.addClause([]int{-lit, ge[v]})
+		c.addClause([]int{-lit, -ge[v+1]})
+		c.addClause([]int{lit, -ge[v], ge[v+1]})
+	}
+	return ge
+}
@@ -0,0 +1,40 @@
+package sudokusolver
+
+import "github.com/rkkautsar/sudoku-solver/sudoku"
+
+// Each cage holds distinct values and picks exactly one of the combinations
+// of distinct values adding up to its sum; a picked combination rules out
+// every other value in the cage's cells.
+func buildCNFCageConstraints(c CNFInterface, builder CNFBuilder) {
+	b := c.getBoard()
+
+	for _, cage := range b.Cages {
+		for v := 1; v <= b.Size2; v++ {
+			lits := make([]int, len(cage.Cells))
+			for i, idx := range cage.Cells {
+				lits[i] = b.CLit(idx/b.Size2, idx%b.Size2, v)
+			}
+			cnfAtMost1(c, filterZero(lits))
+		}
+
+		combinations := sudoku.SumCombinations(len(cage.Cells), cage.Sum, b.Size2)
+		selectors := c.requestLiterals(uint32(len(combinations)))
+		c.addFormula(selectors, builder)
+
+		for i, combination := range combinations {
+			inCombination := make([]bool, b.Size2+1)
+			for _, v := range combination {
+				inCombination[v] = true
+			}
+			for _, idx := range cage.Cells {
+				for v := 1; v <= b.Size2; v++ {
+					lit := b.CLit(idx/b.Size2, idx%b.Size2, v)
+					if !inCombination[v] && lit != 0 {
+						// combination -> -lit
+						c.addClause([]int{-selectors[i], -lit})
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package sudokusolver
+
+import "github.com/rkkautsar/sudoku-solver/sudoku"
+
+// sumTerm adds value to a running sum when all of its lits are true.
+type sumTerm struct {
+	value int
+	lits  []int
+}
+
+// Thermometers are already encoded by their pair constraints. Arrows and
+// sandwiches go through running sums: one-hot layers of partial sums, one
+// layer per cell.
+func buildCNFLineConstraints(c CNFInterface) {
+	b := c.getBoard()
+
+	for _, line := range b.Lines {
+		switch line.Kind {
+		case sudoku.Arrow:
+			buildCNFArrow(c, line)
+		case sudoku.Sandwich:
+			buildCNFSandwich(c, line)
+		}
+	}
+}
+
+func buildCNFArrow(c CNFInterface, line sudoku.Line) {
+	b := c.getBoard()
+	circle := line.Cells[0]
+
+	cells := make([][]sumTerm, 0, len(line.Cells)-1)
+	for _, idx := range line.Cells[1:] {
+		cells = append(cells, valueTerms(b, idx, nil))
+	}
+
+	sums := cnfSum(c, cells, b.Size2)
+	for k, sum := range sums {
+		lit := 0
+		if k > 0 {
+			lit = b.CLit(circle/b.Size2, circle%b.Size2, k)
+		}
+		if lit == 0 {
+			c.addClause([]int{-sum})
+			continue
+		}
+		// sum <-> circle
+		c.addClause([]int{-sum, lit})
+		c.addClause([]int{-lit, sum})
+	}
+}
+
+// A cell is between the 1 and the Size2 if one of them is before it and the
+// other after it, tracked by prefix literals for "1 is before the i-th cell"
+// and the same for Size2.
+func buildCNFSandwich(c CNFInterface, line sudoku.Line) {
+	b := c.getBoard()
+	n := len(line.Cells)
+
+	t := cnfTrue(c)
+	low := make([]int, n+1)
+	high := make([]int, n+1)
+	low[0], high[0] = -t, -t
+	for i, idx := range line.Cells {
+		low[i+1] = cnfOr(c, low[i], b.CLit(idx/b.Size2, idx%b.Size2, 1))
+		high[i+1] = cnfOr(c, high[i], b.CLit(idx/b.Size2, idx%b.Size2, b.Size2))
+	}
+
+	cells := make([][]sumTerm, n)
+	for i, idx := range line.Cells {
+		between := cnfOr(c,
+			cnfAnd(c, low[i], -high[i+1]),
+			cnfAnd(c, high[i], -low[i+1]),
+		)
+		cells[i] = append(valueTerms(b, idx, []int{between}), sumTerm{0, []int{-between}})
+	}
+
+	sums := cnfSum(c, cells, line.Sum)
+	c.addLit(sums[line.Sum])
+}
+
+// valueTerms adds the value of the cell idx when its lits and the cell's
+// literal for the value are true.
+func valueTerms(b *sudoku.Board, idx int, lits []int) []sumTerm {
+	var terms []sumTerm
+	for v := 1; v <= b.Size2; v++ {
+		lit := b.CLit(idx/b.Size2, idx%b.Size2, v)
+		if lit != 0 {
+			terms = append(terms, sumTerm{v, append(append([]int(nil), lits...), lit)})
+		}
+	}
+	return terms
+}
+
+// cnfSum adds up cells, each of which has exactly one true term, and returns
+// the literals of the total indexed by sum. Sums above max are forbidden.
+func cnfSum(c CNFInterface, cells [][]sumTerm, max int) []int {
+	prev := []int{cnfTrue(c)}
+	for _, terms := range cells {
+		layer := c.requestLiterals(uint32(max + 1))
+		c.addFormula(layer, cnfExactly1)
+		for k, sum := range prev {
+			for _, term := range terms {
+				// sum and term -> sum + value
+				clause := []int{-sum}
+				for _, lit := range term.lits {
+					clause = append(clause, -lit)
+				}
+				if k+term.value <= max {
+					clause = append(clause, layer[k+term.value])
+				}
+				c.addClause(clause)
+			}
+		}
+		prev = layer
+	}
+	return prev
+}
+
+func cnfTrue(c CNFInterface) int {
+	t := c.requestLiterals(1)[0]
+	c.addLit(t)
+	return t
+}
+
+// cnfOr returns a literal equivalent to a or b, where 0 is a missing literal.
+func cnfOr(c CNFInterface, a, b int) int {
+	if b == 0 {
+		return a
+	}
+	o := c.requestLiterals(1)[0]
+	c.addClause([]int{-o, a, b})
+	c.addClause([]int{o, -a})
+	c.addClause([]int{o, -b})
+	return o
+}
+
+// cnfAnd returns a literal equivalent to a and b.
+func cnfAnd(c CNFInterface, a, b int) int {
+	o := c.requestLiterals(1)[0]
+	c.addClause([]int{-o, a})
+	c.addClause([]int{-o, b})
+	c.addClause([]int{o, -a, -b})
+	return o
+}
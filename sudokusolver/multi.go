@@ -0,0 +1,146 @@
+package sudokusolver
+
+import (
+	"io"
+
+	"github.com/irifrance/gini"
+	"github.com/irifrance/gini/z"
+	"github.com/rkkautsar/sudoku-solver/cdcl"
+	"github.com/rkkautsar/sudoku-solver/sudoku"
+)
+
+// gridCNF encodes one grid of a sudoku.Multi into the shared CNF, translating
+// the grid's compressed literals into the shared variables.
+type gridCNF struct {
+	shared *CNF
+	board  *sudoku.Board
+	vars   []int // grid variable -> shared variable, 1-indexed
+}
+
+func (c *gridCNF) lit(lit int) int {
+	if lit < 0 {
+		return -c.vars[-lit]
+	}
+	return c.vars[lit]
+}
+
+func (c *gridCNF) addLit(lit int) {
+	c.shared.addLit(c.lit(lit))
+}
+
+func (c *gridCNF) addClause(clause []int) {
+	shared := make([]int, len(clause))
+	for i, lit := range clause {
+		shared[i] = c.lit(lit)
+	}
+	c.shared.addClause(shared)
+}
+
+func (c *gridCNF) addFormula(lits []int, builder CNFBuilder) {
+	builder(c, lits)
+}
+
+func (c *gridCNF) requestLiterals(num uint32) []int {
+	lits := makeRange(uint32(len(c.vars)), uint32(len(c.vars))+num-1)
+	c.vars = append(c.vars, c.shared.requestLiterals(num)...)
+	return lits
+}
+
+func (c *gridCNF) getBoard() *sudoku.Board {
+	return c.board
+}
+
+func (c *gridCNF) getOptions() *EncoderOptions {
+	return c.shared.getOptions()
+}
+
+func (c *gridCNF) Print(w io.Writer) error {
+	return c.shared.Print(w)
+}
+
+// multiCNF is the shared CNF of a sudoku.Multi.
+type multiCNF struct {
+	*CNF
+	vars [][]int // grid -> grid variable -> shared variable
+}
+
+// GenerateMultiCNFConstraints encodes every grid of m into one formula, where
+// a shared cell has the same variables in all of its grids.
+func GenerateMultiCNFConstraints(m *sudoku.Multi, sink ClauseSink, opts EncoderOptions) CNFInterface {
+	return generateMultiCNF(m, sink, opts)
+}
+
+func generateMultiCNF(m *sudoku.Multi, sink ClauseSink, opts EncoderOptions) *multiCNF {
+	m.SyncCandidates()
+	cnf := &CNF{sink: sink, opts: opts}
+
+	// candidates of the same layout cell share their variable
+	vars := make([][]int, len(m.Grids))
+	shared := map[[2]int]int{}
+	for i, grid := range m.Grids {
+		grid.InitCompressedLits()
+		vars[i] = make([]int, grid.NumCandidates+1)
+		corner := m.Corners[i]
+		for idx := range grid.Lookup {
+			row, col := idx/grid.Size2, idx%grid.Size2
+			cell := m.Idx(corner[0]+row, corner[1]+col)
+			for v := 1; v <= grid.Size2; v++ {
+				lit := grid.CLit(row, col, v)
+				if lit == 0 {
+					continue
+				}
+				if _, ok := shared[[2]int{cell, v}]; !ok {
+					shared[[2]int{cell, v}] = cnf.requestLiterals(1)[0]
+				}
+				vars[i][lit] = shared[[2]int{cell, v}]
+			}
+		}
+	}
+
+	for i, grid := range m.Grids {
+		// the aux variables requested by a grid are appended to its copy
+		buildCNF(&gridCNF{shared: cnf, board: grid, vars: vars[i]})
+	}
+	return &multiCNF{cnf, vars}
+}
+
+// solveWithModel fills every grid from the values of the shared variables.
+func (c *multiCNF) solveWithModel(m *sudoku.Multi, value func(lit int) bool) {
+	for i, grid := range m.Grids {
+		model := make([]bool, grid.NumCandidates)
+		for lit := 1; lit <= grid.NumCandidates; lit++ {
+			model[lit-1] = value(c.vars[i][lit])
+		}
+		grid.SolveWithModel(model)
+	}
+}
+
+// SolveMultiWithGini solves every grid of m at once.
+func SolveMultiWithGini(m *sudoku.Multi, opts EncoderOptions) (Result, error) {
+	m.BasicSolve()
+	g := gini.New()
+	cnf := generateMultiCNF(m, GiniSink(g), opts)
+
+	status := g.Solve()
+	if status < 0 {
+		return Unsatisfiable, ErrUnsatisfiable
+	}
+	if status == 0 {
+		return Unknown, ErrUnknown
+	}
+	cnf.solveWithModel(m, func(lit int) bool { return g.Value(z.Dimacs2Lit(lit)) })
+	return Solved, nil
+}
+
+// SolveMultiWithCDCL is SolveMultiWithGini with the native solver.
+func SolveMultiWithCDCL(m *sudoku.Multi, opts EncoderOptions) (Result, error) {
+	m.BasicSolve()
+	s := cdcl.New()
+	cnf := generateMultiCNF(m, s, opts)
+
+	if s.Solve() < 0 {
+		return Unsatisfiable, ErrUnsatisfiable
+	}
+	cnf.solveWithModel(m, s.Value)
+	return Solved, nil
+}
@@ -0,0 +1,115 @@
+package sudokusolver
+
+import (
+	"fmt"
+
+	"github.com/rkkautsar/sudoku-solver/sudoku"
+)
+
+// Encoding selects how the row, column and box constraints are encoded.
+type Encoding int
+
+const (
+	// RangeEncoding encodes exactly-one over the cells of every row, column
+	// and box for each value.
+	RangeEncoding Encoding = iota
+	// TriadEncoding encodes the houses through band and stack triads, as in
+	// tdoku: https://t-dillon.github.io/tdoku/
+	// Jigsaw regions and latin squares have no triads, so they're encoded
+	// with ranges instead, see EncoderOptions.Validate.
+	TriadEncoding
+)
+
+var encodingNames = map[Encoding]string{
+	RangeEncoding: "range",
+	TriadEncoding: "triad",
+}
+
+func (e Encoding) String() string {
+	if name, ok := encodingNames[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+func ParseEncoding(name string) (Encoding, error) {
+	for e, n := range encodingNames {
+		if n == name {
+			return e, nil
+		}
+	}
+	return 0, fmt.Errorf("sudokusolver: unknown encoding %q", name)
+}
+
+// AtMostOneEncoding selects how at-most-one constraints are encoded.
+type AtMostOneEncoding int
+
+const (
+	// AutoAtMostOne uses pairwise for up to 5 literals, commander for up to 10
+	// and bimander above that.
+	AutoAtMostOne AtMostOneEncoding = iota
+	PairwiseAtMostOne
+	CommanderAtMostOne
+	BimanderAtMostOne
+	BinaryAtMostOne
+	SequentialAtMostOne
+	LadderAtMostOne
+	ProductAtMostOne
+)
+
+var atMostOneNames = map[AtMostOneEncoding]string{
+	AutoAtMostOne:       "auto",
+	PairwiseAtMostOne:   "pairwise",
+	CommanderAtMostOne:  "commander",
+	BimanderAtMostOne:   "bimander",
+	BinaryAtMostOne:     "binary",
+	SequentialAtMostOne: "sequential",
+	LadderAtMostOne:     "ladder",
+	ProductAtMostOne:    "product",
+}
+
+func (e AtMostOneEncoding) String() string {
+	if name, ok := atMostOneNames[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("AtMostOneEncoding(%d)", int(e))
+}
+
+func ParseAtMostOneEncoding(name string) (AtMostOneEncoding, error) {
+	for e, n := range atMostOneNames {
+		if n == name {
+			return e, nil
+		}
+	}
+	return 0, fmt.Errorf("sudokusolver: unknown at-most-one encoding %q", name)
+}
+
+type EncoderOptions struct {
+	Encoding  Encoding
+	AtMostOne AtMostOneEncoding
+
+	// Literals per group in the commander and bimander encodings, values
+	// below 2 fall back to COMMANDER_FACTOR and BIMANDER_FACTOR.
+	CommanderGroupSize int
+	BimanderGroupSize  int
+}
+
+// DefaultEncoderOptions returns the options the command line starts from:
+// the range encoding with automatic at-most-one encodings.
+func DefaultEncoderOptions() EncoderOptions {
+	return EncoderOptions{
+		Encoding:           RangeEncoding,
+		AtMostOne:          AutoAtMostOne,
+		CommanderGroupSize: COMMANDER_FACTOR,
+		BimanderGroupSize:  BIMANDER_FACTOR,
+	}
+}
+
+// Validate returns ErrTriadNoBoxes if the options ask for the triad encoding
+// of a board without regular boxes, which would be encoded with ranges.
+func (o EncoderOptions) Validate(b *sudoku.Board) error {
+	if o.Encoding == TriadEncoding && !b.RegularBlocks() {
+		return ErrTriadNoBoxes
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package sudokusolver
+
+// Each pair of values a related pair of cells can't hold together becomes a
+// binary clause.
+func buildCNFPairConstraints(c CNFInterface) {
+	b := c.getBoard()
+
+	for _, pair := range b.PairConstraints {
+		for va := 1; va <= b.Size2; va++ {
+			litA := b.CLit(pair.A/b.Size2, pair.A%b.Size2, va)
+			if litA == 0 {
+				continue
+			}
+			for vb := 1; vb <= b.Size2; vb++ {
+				litB := b.CLit(pair.B/b.Size2, pair.B%b.Size2, vb)
+				if litB != 0 && !pair.Relation(va, vb) {
+					c.addClause([]int{-litA, -litB})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package sudokusolver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rkkautsar/sudoku-solver/cdcl"
+	"github.com/rkkautsar/sudoku-solver/sudoku"
+)
+
+// Rating is how hard a puzzle is, for a human by the hardest technique its
+// solution needs and for a SAT solver by the work the search took. The
+// conflicts and decisions are those of the native cdcl solver, as gini keeps
+// its statistics internal.
+type Rating struct {
+	Score      float64           `json:"score"`
+	Difficulty sudoku.Difficulty `json:"difficulty"`
+	Hardest    sudoku.Technique  `json:"hardest"`
+	Steps      int               `json:"steps"`
+	Conflicts  int               `json:"conflicts"`
+	Decisions  int               `json:"decisions"`
+}
+
+// String formats the rating like
+// 4.2 hard (xy-wing), 61 steps, 0 conflicts, 3 decisions
+func (r Rating) String() string {
+	return fmt.Sprintf("%.1f %s (%s), %d steps, %d conflicts, %d decisions",
+		r.Score, r.Difficulty, r.Hardest, r.Steps, r.Conflicts, r.Decisions)
+}
+
+// Rate rates the board, leaving it solved. It's solved once with cdcl, whose
+// model also gives the placements of the search step when the techniques get
+// stuck.
+func Rate(board *sudoku.Board, opts EncoderOptions) (Rating, error) {
+	solution := board.Clone()
+	s := cdcl.New()
+	GenerateCNFConstraints(solution, s, opts)
+	if s.Solve() < 0 {
+		return Rating{}, ErrUnsatisfiable
+	}
+	solution.SolveWithModel(readModel(solution.NumCandidates, s.Value))
+
+	path := board.RatedPath()
+	if !board.Solved() {
+		path.RecordSearch(board, solution)
+	}
+	hardest, score := path.Hardest()
+	return Rating{
+		Score:      score,
+		Difficulty: sudoku.DifficultyOf(score),
+		Hardest:    hardest,
+		Steps:      len(path.Steps),
+		Conflicts:  s.Conflicts,
+		Decisions:  s.Decisions,
+	}, nil
+}
+
+// RateMany rates every one-line 9x9 puzzle read from in, writing the puzzle,
+// score, difficulty, hardest technique, steps, conflicts and decisions as one
+// comma-separated line each. Like SolveManyGini, a puzzle that can't be
+// solved is reported by its result and the first failure is returned at the
+// end.
+func RateMany(in io.Reader, out io.Writer, opts EncoderOptions) (Result, error) {
+	scanner := bufio.NewScanner(in)
+	writer := bufio.NewWriter(out)
+	board := sudoku.New(3)
+	firstResult, firstErr := Solved, error(nil)
+
+	for line := 1; scanner.Scan(); line++ {
+		input := scanner.Text()
+		writer.WriteString(input + ",")
+		err := board.ReplaceWithSingleRowString(input, false)
+		var rating Rating
+		if err == nil {
+			rating, err = Rate(board, opts)
+		}
+		if err != nil {
+			result := Unknown
+			if errors.Is(err, ErrUnsatisfiable) {
+				result = Unsatisfiable
+			}
+			fmt.Fprintln(writer, result)
+			if firstErr == nil {
+				firstResult, firstErr = result, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+		fmt.Fprintf(writer, "%.1f,%s,%s,%d,%d,%d\n",
+			rating.Score, rating.Difficulty, rating.Hardest, rating.Steps, rating.Conflicts, rating.Decisions)
+	}
+	writer.Flush()
+
+	if err := scanner.Err(); err != nil && firstErr == nil {
+		return SolverError, err
+	}
+	return firstResult, firstErr
+}
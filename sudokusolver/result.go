@@ -0,0 +1,33 @@
+package sudokusolver
+
+import "errors"
+
+// Result is the outcome of solving a single board.
+type Result int
+
+const (
+	Unknown Result = iota
+	Solved
+	Unsatisfiable
+	SolverError
+)
+
+var (
+	ErrUnsatisfiable = errors.New("sudokusolver: puzzle is unsatisfiable")
+	ErrUnknown       = errors.New("sudokusolver: solver returned unknown")
+	ErrNotPrintable  = errors.New("sudokusolver: clause sink can't write the formula")
+	ErrTriadNoBoxes  = errors.New("sudokusolver: the triad encoding needs regular boxes")
+)
+
+func (r Result) String() string {
+	switch r {
+	case Solved:
+		return "solved"
+	case Unsatisfiable:
+		return "unsatisfiable"
+	case SolverError:
+		return "solver error"
+	default:
+		return "unknown"
+	}
+}
@@ -2,6 +2,7 @@ package sudokusolver
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
@@ -9,58 +10,89 @@ import (
 	"strings"
 
 	"github.com/irifrance/gini"
-	"github.com/irifrance/gini/z"
+	"github.com/rkkautsar/sudoku-solver/cdcl"
 	"github.com/rkkautsar/sudoku-solver/sudoku"
 )
 
-func SolveWithGini(board *sudoku.Board) {
+func SolveWithGini(board *sudoku.Board, opts EncoderOptions) (Result, error) {
 	board.BasicSolve()
 	g := gini.NewVc(2*board.NumCandidates, 3*board.NumCandidates)
-	GenerateCNFConstraints(board, g)
-	giniSolve(g, board)
+	GenerateCNFConstraints(board, GiniSink(g), opts)
+	return giniSolve(g, board)
 }
 
-func giniSolve(g *gini.Gini, board *sudoku.Board) {
+func giniSolve(g *gini.Gini, board *sudoku.Board) (Result, error) {
 	status := g.Solve()
 
 	if status < 0 {
-		ms := g.Why([]z.Lit{})
-		fmt.Println(ms)
-		panic("UNSAT")
+		return Unsatisfiable, ErrUnsatisfiable
 	}
-	model := make([]bool, board.NumCandidates)
-	for i := 1; i <= len(model); i++ {
-		model[i-1] = g.Value(z.Dimacs2Lit(i))
+	if status == 0 {
+		return Unknown, ErrUnknown
 	}
-	// log.Println(model)
-	board.SolveWithModel(model)
+	board.SolveWithModel(giniModel(g, board.NumCandidates))
+	return Solved, nil
+}
+
+// SolveWithCDCL solves the board with the native solver from package cdcl.
+func SolveWithCDCL(board *sudoku.Board, opts EncoderOptions) (Result, error) {
+	board.BasicSolve()
+	s := cdcl.New()
+	GenerateCNFConstraints(board, s, opts)
+
+	if s.Solve() < 0 {
+		return Unsatisfiable, ErrUnsatisfiable
+	}
+	board.SolveWithModel(readModel(board.NumCandidates, s.Value))
+	return Solved, nil
 }
 
-func SolveWithCustomSolver(board *sudoku.Board, solver string) {
-	solverArgs := strings.Split(solver, " ")
+func SolveWithCustomSolver(board *sudoku.Board, solver string, opts EncoderOptions) (Result, error) {
+	solverArgs := strings.Fields(solver)
+	if len(solverArgs) == 0 {
+		return SolverError, errors.New("sudokusolver: empty solver command")
+	}
 	cmd := exec.Command(solverArgs[0], solverArgs[1:]...)
-	stdin, _ := cmd.StdinPipe()
-	stdout, _ := cmd.StdoutPipe()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return SolverError, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return SolverError, err
+	}
 	reader := bufio.NewScanner(stdout)
 	writer := bufio.NewWriter(stdin)
 
-	cmd.Start()
-	defer cmd.Wait()
+	if err := cmd.Start(); err != nil {
+		return SolverError, err
+	}
 	board.BasicSolve()
 	g := gini.NewVc(2*board.NumCandidates, 3*board.NumCandidates)
-	cnf := GenerateCNFConstraints(board, g)
-	cnf.Print(writer)
-	writer.Flush()
+	cnf := GenerateCNFConstraints(board, GiniSink(g), opts)
+	err = cnf.Print(writer)
+	if err == nil {
+		err = writer.Flush()
+	}
 	stdin.Close()
+	if err != nil {
+		cmd.Wait()
+		return SolverError, err
+	}
 
 	model := make([]bool, board.NumCandidates)
+	result := Unknown
 
 	for reader.Scan() {
 		line := reader.Text()
 
 		if strings.HasPrefix(line, "s UNSATISFIABLE") {
-			fmt.Println("UNSAT")
-			return
+			result = Unsatisfiable
+			continue
+		}
+		if strings.HasPrefix(line, "s SATISFIABLE") {
+			result = Solved
+			continue
 		}
 
 		if len(line) < 1 || !strings.HasPrefix(line, "v") {
@@ -76,16 +108,47 @@ func SolveWithCustomSolver(board *sudoku.Board, solver string) {
 				parsed = -parsed
 			}
 
-			if parsed > 0 && parsed < len(model) {
+			if parsed > 0 && parsed <= len(model) {
 				model[parsed-1] = polarity
 			}
 		}
 	}
 
+	// SAT solvers conventionally exit with 10 (SAT) or 20 (UNSAT), so the
+	// exit status only matters when no answer was printed.
+	if err := cmd.Wait(); err != nil && result == Unknown {
+		return SolverError, fmt.Errorf("sudokusolver: %s: %w", solver, err)
+	}
+
+	switch result {
+	case Unsatisfiable:
+		return Unsatisfiable, ErrUnsatisfiable
+	case Unknown:
+		return Unknown, ErrUnknown
+	}
+
 	board.SolveWithModel(model)
+	return Solved, nil
+}
+
+// SolveManyGini solves every one-line 9x9 puzzle read from in. A puzzle that
+// can't be solved is reported by its result in place of the solution, and the
+// first failure is returned after the whole input has been processed.
+func SolveManyGini(in io.Reader, out io.Writer, opts EncoderOptions) (Result, error) {
+	return solveMany(in, out, opts, SolveWithGini)
 }
 
-func SolveManyGini(in io.Reader, out io.Writer) {
+// SolveManyCDCL is SolveManyGini with the native solver.
+func SolveManyCDCL(in io.Reader, out io.Writer, opts EncoderOptions) (Result, error) {
+	return solveMany(in, out, opts, SolveWithCDCL)
+}
+
+func solveMany(
+	in io.Reader,
+	out io.Writer,
+	opts EncoderOptions,
+	solve func(*sudoku.Board, EncoderOptions) (Result, error),
+) (Result, error) {
 	shouldPrintPuzzle := false
 
 	if out == nil {
@@ -97,15 +160,30 @@ func SolveManyGini(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
 	writer := bufio.NewWriter(out)
 	board := sudoku.New(3)
+	firstResult, firstErr := Solved, error(nil)
 
-	for scanner.Scan() {
+	for line := 1; scanner.Scan(); line++ {
 		input := scanner.Text()
 		if shouldPrintPuzzle {
 			writer.WriteString(input + ",")
 		}
-		board.ReplaceWithSingleRowString(input, false)
-		SolveWithGini(board)
+		result, err := Unknown, board.ReplaceWithSingleRowString(input, false)
+		if err == nil {
+			result, err = solve(board, opts)
+		}
+		if err != nil {
+			fmt.Fprintln(writer, result)
+			if firstErr == nil {
+				firstResult, firstErr = result, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
 		board.PrintOneLine(writer)
 	}
 	writer.Flush()
+
+	if err := scanner.Err(); err != nil && firstErr == nil {
+		return SolverError, err
+	}
+	return firstResult, firstErr
 }
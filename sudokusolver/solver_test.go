@@ -2,6 +2,7 @@ package sudokusolver_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -12,6 +13,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/irifrance/gini"
+	"github.com/rkkautsar/sudoku-solver/cdcl"
 	"github.com/rkkautsar/sudoku-solver/sudoku"
 	"github.com/rkkautsar/sudoku-solver/sudokusolver"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +37,8 @@ var hard17clue = [...]string{
 	"693784512487512936125963874932651487568247391741398625319475268856129743274836159",
 }
 
+var defaults = sudokusolver.DefaultEncoderOptions()
+
 func TestMain(m *testing.M) {
 	log.SetOutput(ioutil.Discard)
 	os.Exit(m.Run())
@@ -54,13 +59,488 @@ func TestSolveHard17clue(t *testing.T) {
 	assert.Equal(t, hard17clue[1], solution)
 }
 
+func TestSolveUnsatisfiable(t *testing.T) {
+	// two 1s in the first row
+	board := sudoku.NewFromString("11" + strings.Repeat("0", 79))
+	result, err := sudokusolver.SolveWithGini(board, defaults)
+	assert.Equal(t, sudokusolver.Unsatisfiable, result)
+	assert.ErrorIs(t, err, sudokusolver.ErrUnsatisfiable)
+
+	// the last cell of the first row has no candidate left
+	board = sudoku.NewFromString("123456780000000009" + strings.Repeat("0", 63))
+	result, err = sudokusolver.SolveWithGini(board, defaults)
+	assert.Equal(t, sudokusolver.Unsatisfiable, result)
+	assert.ErrorIs(t, err, sudokusolver.ErrUnsatisfiable)
+}
+
+func TestSolveManyReportsUnsatisfiable(t *testing.T) {
+	in := strings.NewReader(aiEscargot[0] + "\n" + "11" + strings.Repeat("0", 79) + "\n")
+	var out bytes.Buffer
+	result, err := sudokusolver.SolveManyGini(in, &out, defaults)
+	assert.Equal(t, sudokusolver.Unsatisfiable, result)
+	assert.ErrorIs(t, err, sudokusolver.ErrUnsatisfiable)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, aiEscargot[0]+","+aiEscargot[1], lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], ",unsatisfiable"))
+}
+
+func TestPrintCNF(t *testing.T) {
+	var out strings.Builder
+	cnf := sudokusolver.GenerateCNFConstraints(sudoku.New(2), sudokusolver.GiniSink(gini.New()), defaults)
+	assert.NoError(t, cnf.Print(&out))
+	assert.True(t, strings.HasPrefix(out.String(), "p cnf "))
+
+	out.Reset()
+	cnf = sudokusolver.GenerateCNFConstraints(sudoku.New(2), cdcl.New(), defaults)
+	assert.NoError(t, cnf.Print(&out))
+	assert.True(t, strings.HasPrefix(out.String(), "p cnf "))
+
+	var clauses clauseCounter
+	cnf = sudokusolver.GenerateCNFConstraints(sudoku.New(2), &clauses, defaults)
+	assert.ErrorIs(t, cnf.Print(&out), sudokusolver.ErrNotPrintable)
+	assert.NotZero(t, clauses)
+}
+
+// clauseCounter is a ClauseSink that can't write the formula.
+type clauseCounter int
+
+func (c *clauseCounter) Add(lit int) {
+	if lit == 0 {
+		*c++
+	}
+}
+
+func TestCountSolutions(t *testing.T) {
+	count, err := sudokusolver.CountSolutions(sudoku.New(2), 0, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, 288, count)
+
+	count, err = sudokusolver.CountSolutions(sudoku.New(2), 10, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, count)
+
+	count, err = sudokusolver.CountSolutions(sudoku.NewFromString("11"+strings.Repeat("0", 79)), 0, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestIsUnique(t *testing.T) {
+	board := sudoku.NewFromString(aiEscargot[0])
+	unique, err := sudokusolver.IsUnique(board, defaults)
+	assert.NoError(t, err)
+	assert.True(t, unique)
+	var b bytes.Buffer
+	board.PrintOneLine(&b)
+	assert.Equal(t, aiEscargot[1], strings.TrimSpace(b.String()))
+
+	unique, err = sudokusolver.IsUnique(sudoku.NewFromString("1"+strings.Repeat("0", 80)), defaults)
+	assert.NoError(t, err)
+	assert.False(t, unique)
+}
+
+func TestEnumerateSolutions(t *testing.T) {
+	solutions, errc := sudokusolver.EnumerateSolutions(context.Background(), sudoku.New(2), 0, defaults)
+	seen := map[string]bool{}
+	for solution := range solutions {
+		var b bytes.Buffer
+		solution.PrintOneLine(&b)
+		assert.NotContains(t, b.String(), "0")
+		seen[b.String()] = true
+	}
+	assert.NoError(t, <-errc)
+	assert.Len(t, seen, 288)
+
+	solutions, errc = sudokusolver.EnumerateSolutions(context.Background(), sudoku.New(2), 3, defaults)
+	count := 0
+	for range solutions {
+		count++
+	}
+	assert.NoError(t, <-errc)
+	assert.Equal(t, 3, count)
+}
+
+func TestEnumerateSolutionsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	solutions, errc := sudokusolver.EnumerateSolutions(ctx, sudoku.New(3), 0, defaults)
+	<-solutions
+	cancel()
+	for range solutions {
+	}
+	assert.ErrorIs(t, <-errc, context.Canceled)
+}
+
+func TestSolveTriadEncoding(t *testing.T) {
+	triad := withEncoding(sudokusolver.TriadEncoding)
+
+	assert.Equal(t, aiEscargot[1], solveOneLinerWith(aiEscargot[0], triad))
+	assert.Equal(t, hard1[1], solveOneLinerWith(hard1[0], triad))
+	assert.Equal(t, hard17clue[1], solveOneLinerWith(hard17clue[0], triad))
+
+	count, err := sudokusolver.CountSolutions(sudoku.New(2), 0, triad)
+	assert.NoError(t, err)
+	assert.Equal(t, 288, count)
+}
+
+func TestSolveAtMostOneEncodings(t *testing.T) {
+	// ai escargot without its first three clues
+	underConstrained := "000000090" + aiEscargot[0][9:]
+	expected, err := sudokusolver.CountSolutions(sudoku.NewFromString(underConstrained), 0, defaults)
+	assert.NoError(t, err)
+
+	for _, amo := range []sudokusolver.AtMostOneEncoding{
+		sudokusolver.PairwiseAtMostOne,
+		sudokusolver.CommanderAtMostOne,
+		sudokusolver.BimanderAtMostOne,
+		sudokusolver.BinaryAtMostOne,
+		sudokusolver.SequentialAtMostOne,
+		sudokusolver.LadderAtMostOne,
+		sudokusolver.ProductAtMostOne,
+	} {
+		opts := withAtMostOne(amo)
+		assert.Equal(t, aiEscargot[1], solveOneLinerWith(aiEscargot[0], opts), amo.String())
+		count, err := sudokusolver.CountSolutions(sudoku.NewFromString(underConstrained), 0, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, count, amo.String())
+		count, err = sudokusolver.CountSolutions(sudoku.New(2), 0, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, 288, count, amo.String())
+	}
+}
+
+func TestSolveCDCL(t *testing.T) {
+	assert.Equal(t, aiEscargot[1], cdclSolveOneLiner(aiEscargot[0]))
+	assert.Equal(t, hard1[1], cdclSolveOneLiner(hard1[0]))
+	assert.Equal(t, hard17clue[1], cdclSolveOneLiner(hard17clue[0]))
+
+	board := sudoku.NewFromString("11" + strings.Repeat("0", 79))
+	result, err := sudokusolver.SolveWithCDCL(board, defaults)
+	assert.Equal(t, sudokusolver.Unsatisfiable, result)
+	assert.ErrorIs(t, err, sudokusolver.ErrUnsatisfiable)
+}
+
+func TestManyHardestCDCL(t *testing.T) {
+	var expected, actual bytes.Buffer
+	file, _ := os.Open("../data/sudoku.many.hardest110626.txt")
+	sudokusolver.SolveManyGini(file, &expected, defaults)
+	file.Close()
+	file, _ = os.Open("../data/sudoku.many.hardest110626.txt")
+	sudokusolver.SolveManyCDCL(file, &actual, defaults)
+	file.Close()
+	assert.Equal(t, expected.String(), actual.String())
+}
+
+func TestSolveKiller(t *testing.T) {
+	bytes, _ := ioutil.ReadFile("../data/killer-9-1.txt")
+	board := sudoku.NewFromString(string(bytes))
+	unique, err := sudokusolver.IsUnique(board, defaults)
+	assert.NoError(t, err)
+	assert.True(t, unique)
+	assert.Equal(t, aiEscargot[1], oneLine(board))
+
+	board = sudoku.NewFromString(string(bytes))
+	assert.Equal(t, aiEscargot[1], cdclSolve(board))
+}
+
+func TestSolveJigsaw(t *testing.T) {
+	const solution = "974361852263958741518742963182437695756189324495623187349516278837295416621874539"
+	bytes, _ := ioutil.ReadFile("../data/jigsaw-9-1.txt")
+	board := sudoku.NewFromString(string(bytes))
+	unique, err := sudokusolver.IsUnique(board, defaults)
+	assert.NoError(t, err)
+	assert.True(t, unique)
+	assert.Equal(t, solution, oneLine(board))
+
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	assert.ErrorIs(t, triad.Validate(board), sudokusolver.ErrTriadNoBoxes)
+	assert.Equal(t, solution, solveOneLinerWith(string(bytes), triad))
+}
+
+func TestSolveRectangular(t *testing.T) {
+	for file, solution := range map[string]string{
+		"../data/sudoku-6-1.txt": "653142412563526431341625265314134256",
+		"../data/sudoku-8-1.txt": "3164287557826143254173868673425143568712182756346418352772351468",
+		"../data/sudoku-12-1.txt": "216741191035812985432712101611310111256184729637521129811410141021183659127891211751041236" +
+			"473191282610115125261101137498101198645712312721389651112104564101232119871111289107412653",
+	} {
+		bytes, _ := ioutil.ReadFile(file)
+		board := sudoku.NewFromString(string(bytes))
+		unique, err := sudokusolver.IsUnique(board, defaults)
+		assert.NoError(t, err, file)
+		assert.True(t, unique, file)
+		assert.Equal(t, solution, oneLine(board), file)
+
+		assert.Equal(t, solution, cdclSolve(sudoku.NewFromString(string(bytes))), file)
+
+		triad := withEncoding(sudokusolver.TriadEncoding)
+		assert.NoError(t, triad.Validate(board), file)
+		assert.Equal(t, solution, solveOneLinerWith(string(bytes), triad), file)
+	}
+
+	// 1x4 boxes are the rows, so these are the latin squares of order 4
+	count, err := sudokusolver.CountSolutions(sudoku.NewRect(1, 4), 0, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, 576, count)
+
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	count, err = sudokusolver.CountSolutions(sudoku.NewRect(1, 4), 0, triad)
+	assert.NoError(t, err)
+	assert.Equal(t, 576, count)
+}
+
+func TestSolveDiagonal(t *testing.T) {
+	const solution = "971235846853649712264871395529314687186927453437586921648752139395168274712493568"
+	bytes, _ := ioutil.ReadFile("../data/sudoku-x-9-1.txt")
+	board := sudoku.NewFromString(string(bytes))
+	unique, err := sudokusolver.IsUnique(board, defaults)
+	assert.NoError(t, err)
+	assert.True(t, unique)
+	assert.Equal(t, solution, oneLine(board))
+
+	board = sudoku.New(2)
+	board.AddDiagonals()
+	count, err := sudokusolver.CountSolutions(board, 0, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, 48, count)
+
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	assert.Equal(t, solution, solveOneLinerWith(string(bytes), triad))
+}
+
+func TestSolveHyper(t *testing.T) {
+	const solution = "247639851186425397395187264532761489619842735874593612768914523421358976953276148"
+	bytes, _ := ioutil.ReadFile("../data/sudoku-hyper-9-1.txt")
+	board := sudoku.NewFromString(string(bytes))
+	unique, err := sudokusolver.IsUnique(board, defaults)
+	assert.NoError(t, err)
+	assert.True(t, unique)
+	assert.Equal(t, solution, oneLine(board))
+	assert.Equal(t, solution, cdclSolveOneLiner(string(bytes)))
+}
+
+func TestSolveAntiKnightAndAntiKing(t *testing.T) {
+	for file, solution := range map[string]string{
+		"../data/sudoku-antiknight-9-1.txt":     "975418263423569718168723945587231694234695871691874532719382456342956187856147329",
+		"../data/sudoku-antiking-9-1.txt":       "875912634162834975493576812721483596538769241946251783659148327217395468384627159",
+		"../data/sudoku-nonconsecutive-9-1.txt": "428316975795842613361579248613795824957428361284163597842631759579284136136957482",
+		"../data/sudoku-kropki-9-1.txt":         "849562137125347986367198452498621573236475891751983624572814369913756248684239715",
+		"../data/sudoku-xv-9-1.txt":             "849562137125347986367198452498621573236475891571983624752814369913756248684239715",
+	} {
+		bytes, _ := ioutil.ReadFile(file)
+		board := sudoku.NewFromString(string(bytes))
+		unique, err := sudokusolver.IsUnique(board, defaults)
+		assert.NoError(t, err, file)
+		assert.True(t, unique, file)
+		assert.Equal(t, solution, oneLine(board), file)
+		assert.Equal(t, solution, cdclSolveOneLiner(string(bytes)), file)
+	}
+}
+
+func TestSolveLines(t *testing.T) {
+	for file, solution := range map[string]string{
+		"../data/sudoku-thermo-arrow-9-1.txt": "849562137125347986367198452498621573236475891571983624752814369913756248684239715",
+		"../data/sudoku-sandwich-9-1.txt":     "841562973925347186367918452418629537256473819739185624572894361193756248684231795",
+	} {
+		bytes, _ := ioutil.ReadFile(file)
+		board := sudoku.NewFromString(string(bytes))
+		unique, err := sudokusolver.IsUnique(board, defaults)
+		assert.NoError(t, err, file)
+		assert.True(t, unique, file)
+		assert.Equal(t, solution, oneLine(board), file)
+		assert.Equal(t, solution, cdclSolveOneLiner(string(bytes)), file)
+	}
+}
+
+func TestSolveInequalities(t *testing.T) {
+	for file, solution := range map[string]string{
+		"../data/sudoku-greater-than-9-1.txt": "849562137125347986367198452498621573236475891571983624752814369913756248684239715",
+		"../data/futoshiki-5-1.txt":           "4152325314134525423132145",
+	} {
+		bytes, _ := ioutil.ReadFile(file)
+		board := sudoku.NewFromString(string(bytes))
+		unique, err := sudokusolver.IsUnique(board, defaults)
+		assert.NoError(t, err, file)
+		assert.True(t, unique, file)
+		assert.Equal(t, solution, oneLine(board), file)
+		assert.Equal(t, solution, cdclSolveOneLiner(string(bytes)), file)
+	}
+}
+
+func TestSolveLatin(t *testing.T) {
+	for file, solution := range map[string]string{
+		"../data/latin-7-1.txt":  "5763142657421376513241342567421573621364753427651",
+		"../data/latin-10-1.txt": "10986751324151089724639823410715647691085231769108345128101269374563512498710347512861095247316109821345610987",
+	} {
+		input, _ := ioutil.ReadFile(file)
+		board := sudoku.NewFromString(string(input))
+		assert.False(t, board.HasBoxes(), file)
+		unique, err := sudokusolver.IsUnique(board, defaults)
+		assert.NoError(t, err, file)
+		assert.True(t, unique, file)
+		assert.Equal(t, solution, oneLine(board), file)
+		assert.Equal(t, solution, cdclSolveOneLiner(string(input)), file)
+	}
+
+	// the number of latin squares of order 3 and 4
+	count, err := sudokusolver.CountSolutions(sudoku.NewLatin(3), 0, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, count)
+	count, err = sudokusolver.CountSolutions(sudoku.NewLatin(4), 0, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, 576, count)
+}
+
+func TestSolveEvenOdd(t *testing.T) {
+	const solution = "241876395985321764367954812176245938829763451534189276758632149413598627692417583"
+	input, _ := ioutil.ReadFile("../data/sudoku-even-odd-9-1.txt")
+	board := sudoku.NewFromString(string(input))
+	unique, err := sudokusolver.IsUnique(board, defaults)
+	assert.NoError(t, err)
+	assert.True(t, unique)
+	assert.Equal(t, solution, oneLine(board))
+
+	oneLiner := "2.....oo...ooe..eeo..954.oe1oe.4o.3.e..76oeoo5o.o.oe.eoo.e.e.49.o.ooe.e..92e..o.3"
+	assert.Equal(t, solution, solveOneLiner(oneLiner))
+	assert.Equal(t, solution, cdclSolveOneLiner(oneLiner))
+
+	var out bytes.Buffer
+	_, err = sudokusolver.SolveManyGini(strings.NewReader(oneLiner+"\n2[13\n"), &out, defaults)
+	assert.Error(t, err)
+	assert.Equal(t, oneLiner+","+solution+"\n2[13,unknown\n", out.String())
+}
+
+const samuraiSolution = `9 7 5 6 4 8 2 1 3 - - - 8 4 9 3 1 2 5 6 7
+4 3 8 1 2 7 9 5 6 - - - 7 3 1 5 6 8 2 9 4
+6 1 2 3 9 5 7 8 4 - - - 5 6 2 9 4 7 3 8 1
+5 8 9 4 6 2 3 7 1 - - - 3 7 5 1 2 6 9 4 8
+7 6 3 8 5 1 4 2 9 - - - 2 8 6 4 9 5 7 1 3
+2 4 1 9 7 3 5 6 8 - - - 9 1 4 8 7 3 6 2 5
+1 5 4 7 8 9 6 3 2 8 5 1 4 9 7 2 5 1 8 3 6
+3 2 6 5 1 4 8 9 7 4 6 2 1 5 3 6 8 9 4 7 2
+8 9 7 2 3 6 1 4 5 3 7 9 6 2 8 7 3 4 1 5 9
+- - - - - - 2 7 9 5 4 8 3 6 1 - - - - - -
+- - - - - - 3 5 6 2 1 7 8 4 9 - - - - - -
+- - - - - - 4 1 8 6 9 3 2 7 5 - - - - - -
+9 8 4 3 2 7 5 6 1 7 8 4 9 3 2 5 4 7 6 8 1
+7 5 3 6 8 1 9 2 4 1 3 5 7 8 6 9 3 1 4 5 2
+2 1 6 5 9 4 7 8 3 9 2 6 5 1 4 8 6 2 9 7 3
+5 4 8 9 6 3 1 7 2 - - - 6 7 9 2 8 5 1 3 4
+6 9 7 8 1 2 4 3 5 - - - 1 4 8 6 9 3 7 2 5
+3 2 1 7 4 5 8 9 6 - - - 2 5 3 7 1 4 8 9 6
+8 6 5 1 3 9 2 4 7 - - - 8 6 1 3 5 9 2 4 7
+4 7 9 2 5 6 3 1 8 - - - 3 9 7 4 2 6 5 1 8
+1 3 2 4 7 8 6 5 9 - - - 4 2 5 1 7 8 3 6 9
+`
+
+func TestSolveSamurai(t *testing.T) {
+	bytes, _ := ioutil.ReadFile("../data/samurai-9-1.txt")
+	for _, solve := range []func(*sudoku.Multi, sudokusolver.EncoderOptions) (sudokusolver.Result, error){
+		sudokusolver.SolveMultiWithGini,
+		sudokusolver.SolveMultiWithCDCL,
+	} {
+		m, err := sudoku.ParseMulti(string(bytes))
+		assert.NoError(t, err)
+		result, err := solve(m, defaults)
+		assert.NoError(t, err)
+		assert.Equal(t, sudokusolver.Solved, result)
+
+		var buf strings.Builder
+		m.Print(&buf)
+		assert.Equal(t, samuraiSolution, buf.String())
+	}
+
+	m, _ := sudoku.ParseMulti(string(bytes))
+	assert.NoError(t, m.SetValue(0, 0, 1))
+	result, err := sudokusolver.SolveMultiWithGini(m, defaults)
+	assert.Equal(t, sudokusolver.Unsatisfiable, result)
+	assert.ErrorIs(t, err, sudokusolver.ErrUnsatisfiable)
+}
+
 func TestMany17clue(t *testing.T) {
 	h := md5.New()
 	fmt.Fprintln(h, 49151)
-	solveManyWithGini("../data/sudoku.many.17clue.txt", h)
+	solveManyWithGini("../data/sudoku.many.17clue.txt", h, defaults)
 	assert.Equal(t, "41704fd7d8fd0723a45ffbb2dbbfa488", hex.EncodeToString(h.Sum(nil)))
 }
 
+func TestExplain(t *testing.T) {
+	path, err := sudokusolver.Explain(sudoku.NewFromSingleRowString(hard1[0]), defaults)
+	assert.NoError(t, err)
+	assert.True(t, path.Searched())
+	search := path.Steps[len(path.Steps)-1]
+	assert.Equal(t, sudoku.Search, search.Technique)
+
+	board := sudoku.NewFromSingleRowString(hard1[0])
+	for _, step := range path.Steps {
+		step.Apply(board)
+	}
+	assert.Equal(t, hard1[1], oneLine(board))
+
+	input, _ := ioutil.ReadFile("../data/sudoku-hyper-9-1.txt")
+	path, err = sudokusolver.Explain(sudoku.NewFromString(string(input)), defaults)
+	assert.NoError(t, err)
+	assert.False(t, path.Searched())
+
+	_, err = sudokusolver.Explain(sudoku.NewFromSingleRowString("11"+strings.Repeat(".", 79)), defaults)
+	assert.ErrorIs(t, err, sudokusolver.ErrUnsatisfiable)
+}
+
+func TestRate(t *testing.T) {
+	input, _ := ioutil.ReadFile("../data/sudoku-hyper-9-1.txt")
+	rating, err := sudokusolver.Rate(sudoku.NewFromString(string(input)), defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, sudoku.SimpleColoring, rating.Hardest)
+	assert.Equal(t, 4.5, rating.Score)
+	assert.Equal(t, sudoku.Hard, rating.Difficulty)
+
+	board := sudoku.NewFromSingleRowString(hard1[0])
+	rating, err = sudokusolver.Rate(board, defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, sudoku.Search, rating.Hardest)
+	assert.Equal(t, sudoku.Expert, rating.Difficulty)
+	assert.NotZero(t, rating.Conflicts)
+	assert.NotZero(t, rating.Decisions)
+	assert.Equal(t, hard1[1], oneLine(board))
+}
+
+func TestRateMany(t *testing.T) {
+	input := strings.Join([]string{
+		"000000010400000000020000000000050407008000300001090000300400200050100000000806000",
+		"000000012008030000000000040120500000000004700060000000507000300000620000000100000",
+		"11" + strings.Repeat(".", 79),
+	}, "\n")
+	var out strings.Builder
+	result, err := sudokusolver.RateMany(strings.NewReader(input), &out, defaults)
+	assert.Equal(t, sudokusolver.Unsatisfiable, result)
+	assert.ErrorIs(t, err, sudokusolver.ErrUnsatisfiable)
+	assert.Equal(t, strings.Join([]string{
+		"000000010400000000020000000000050407008000300001090000300400200050100000000806000,1.5,easy,hidden single,64,0,0",
+		"000000012008030000000000040120500000000004700060000000507000300000620000000100000,2.8,medium,claiming,74,3,6",
+		"11" + strings.Repeat(".", 79) + ",unsatisfiable",
+	}, "\n")+"\n", out.String())
+}
+
+func TestLogicalSolveSound(t *testing.T) {
+	input, _ := ioutil.ReadFile("../data/sudoku.many.17clue.txt")
+	lines := strings.Fields(string(input))[:200]
+	for _, line := range lines {
+		board := sudoku.NewFromSingleRowString(line)
+		solution := board.Clone()
+		_, err := sudokusolver.SolveWithGini(solution, defaults)
+		assert.NoError(t, err)
+
+		for _, step := range board.LogicalSolve() {
+			for _, p := range step.Placements {
+				assert.Equal(t, solution.Lookup[board.Idx(p.Row, p.Col)], p.Value, step.String())
+			}
+			for _, e := range step.Eliminations {
+				assert.NotEqual(t, solution.Lookup[board.Idx(e.Row, e.Col)], e.Value, step.String())
+			}
+		}
+		assert.True(t, board.Solved(), line)
+	}
+}
+
 func BenchmarkSolveAiEscargot(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		solveOneLiner(aiEscargot[0])
@@ -143,6 +623,75 @@ func BenchmarkSolve225x225(b *testing.B) {
 	}
 }
 
+func BenchmarkSolveTriadAiEscargot(b *testing.B) {
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	for i := 0; i < b.N; i++ {
+		solveOneLinerWith(aiEscargot[0], triad)
+	}
+}
+
+func BenchmarkSolveTriadHard9x9(b *testing.B) {
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	for i := 0; i < b.N; i++ {
+		solveOneLinerWith(hard1[0], triad)
+	}
+}
+
+func BenchmarkSolveTriad25x25(b *testing.B) {
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	bytes, _ := ioutil.ReadFile("../data/sudoku-25-1.txt")
+	input := string(bytes)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		solveOneLinerWith(input, triad)
+	}
+}
+
+func BenchmarkSolveCDCLAiEscargot(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		cdclSolveOneLiner(aiEscargot[0])
+	}
+}
+
+func BenchmarkSolveCDCLHard9x9(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		cdclSolveOneLiner(hard1[0])
+	}
+}
+
+func BenchmarkSolveCDCL17clue9x9(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		cdclSolveOneLiner(hard17clue[0])
+	}
+}
+
+func BenchmarkSolveCDCL25x25(b *testing.B) {
+	bytes, _ := ioutil.ReadFile("../data/sudoku-25-1.txt")
+	input := string(bytes)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cdclSolveOneLiner(input)
+	}
+}
+
+func BenchmarkSolveCDCL81x81(b *testing.B) {
+	bytes, _ := ioutil.ReadFile("../data/sudoku-81-1.txt")
+	input := string(bytes)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cdclSolveOneLiner(input)
+	}
+}
+
+func BenchmarkSolveCDCL100x100(b *testing.B) {
+	bytes, _ := ioutil.ReadFile("../data/sudoku-100-1.txt")
+	input := string(bytes)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cdclSolveOneLiner(input)
+	}
+}
+
 func BenchmarkSolveWithCadicalAiEscargot(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		customSolveOneLiner(aiEscargot[0], CUSTOM_SOLVER)
@@ -226,26 +775,75 @@ func BenchmarkSolveWithCadical225x225(b *testing.B) {
 
 func BenchmarkSolveManyHardest110626(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		solveMany("../data/sudoku.many.hardest110626.txt")
+		solveMany("../data/sudoku.many.hardest110626.txt", defaults)
 	}
 }
 
 func BenchmarkSolveMany17Clue2k(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		solveMany("../data/sudoku.many.17clue.2k.txt")
+		solveMany("../data/sudoku.many.17clue.2k.txt", defaults)
+	}
+}
+
+func BenchmarkSolveManyTriadHardest110626(b *testing.B) {
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	for i := 0; i < b.N; i++ {
+		solveMany("../data/sudoku.many.hardest110626.txt", triad)
+	}
+}
+
+func BenchmarkSolveManyTriad17Clue2k(b *testing.B) {
+	triad := withEncoding(sudokusolver.TriadEncoding)
+	for i := 0; i < b.N; i++ {
+		solveMany("../data/sudoku.many.17clue.2k.txt", triad)
+	}
+}
+
+func BenchmarkSolveManyCDCLHardest110626(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		file, _ := os.Open("../data/sudoku.many.hardest110626.txt")
+		sudokusolver.SolveManyCDCL(file, nil, defaults)
+		file.Close()
+	}
+}
+
+func BenchmarkSolveManyCDCL17Clue2k(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		file, _ := os.Open("../data/sudoku.many.17clue.2k.txt")
+		sudokusolver.SolveManyCDCL(file, nil, defaults)
+		file.Close()
 	}
 }
 
 func BenchmarkSolveMany17Clue(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		solveMany("../data/sudoku.many.17clue.txt")
+		solveMany("../data/sudoku.many.17clue.txt", defaults)
 	}
 }
 
 func solveOneLiner(input string) string {
+	return solveOneLinerWith(input, defaults)
+}
+
+func solveOneLinerWith(input string, opts sudokusolver.EncoderOptions) string {
 	board := sudoku.NewFromString(input)
 	// sudokusolver.Solve(board)
-	sudokusolver.SolveWithGini(board)
+	sudokusolver.SolveWithGini(board, opts)
+	var b bytes.Buffer
+	board.PrintOneLine(&b)
+	return strings.TrimSpace(b.String())
+}
+
+func cdclSolveOneLiner(input string) string {
+	return cdclSolve(sudoku.NewFromString(input))
+}
+
+func cdclSolve(board *sudoku.Board) string {
+	sudokusolver.SolveWithCDCL(board, defaults)
+	return oneLine(board)
+}
+
+func oneLine(board *sudoku.Board) string {
 	var b bytes.Buffer
 	board.PrintOneLine(&b)
 	return strings.TrimSpace(b.String())
@@ -253,17 +851,32 @@ func solveOneLiner(input string) string {
 
 func customSolveOneLiner(input, solver string) string {
 	board := sudoku.NewFromString(input)
-	sudokusolver.SolveWithCustomSolver(board, solver)
+	sudokusolver.SolveWithCustomSolver(board, solver, defaults)
 	var b bytes.Buffer
 	board.PrintOneLine(&b)
 	return strings.TrimSpace(b.String())
 }
 
-func solveMany(inputFile string) {
-	solveManyWithGini(inputFile, nil)
+func solveMany(inputFile string, opts sudokusolver.EncoderOptions) {
+	solveManyWithGini(inputFile, nil, opts)
 }
 
-func solveManyWithGini(inputFile string, output io.Writer) {
+func solveManyWithGini(inputFile string, output io.Writer, opts sudokusolver.EncoderOptions) {
 	file, _ := os.Open(inputFile)
-	sudokusolver.SolveManyGini(file, output)
+	sudokusolver.SolveManyGini(file, output, opts)
+}
+
+// withEncoding returns the default options with the given encoding.
+func withEncoding(encoding sudokusolver.Encoding) sudokusolver.EncoderOptions {
+	opts := sudokusolver.DefaultEncoderOptions()
+	opts.Encoding = encoding
+	return opts
+}
+
+// withAtMostOne returns the default options with the given at-most-one
+// encoding.
+func withAtMostOne(amo sudokusolver.AtMostOneEncoding) sudokusolver.EncoderOptions {
+	opts := sudokusolver.DefaultEncoderOptions()
+	opts.AtMostOne = amo
+	return opts
 }